@@ -5,14 +5,31 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"go.uber.org/zap"
 
 	"github.com/Thrun12/golang-assignment/internal/config"
+	"github.com/Thrun12/golang-assignment/internal/logging"
 )
 
+// migrateLogger passes the progress messages of migrate to a zap logger at
+// debug level
+type migrateLogger struct {
+	log *zap.Logger
+}
+
+func (l migrateLogger) Printf(format string, v ...interface{}) {
+	l.log.Debug(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (l migrateLogger) Verbose() bool {
+	return l.log.Core().Enabled(zap.DebugLevel)
+}
+
 func main() {
 	var (
 		direction string
@@ -30,24 +47,33 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Initialize logger
+	log, _, err := logging.New(cfg.GetLoggingConfig())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer func() {
+		_ = log.Sync()
+	}()
+
+	if direction != "up" && direction != "down" {
+		log.Fatal("invalid direction, must be 'up' or 'down'",
+			zap.String("direction", direction),
+		)
+	}
+
 	// Create migrator
 	m, err := migrate.New(
 		fmt.Sprintf("file://%s", cfg.MigrationPath),
 		cfg.DatabaseURL,
 	)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to create migrator: %v\n", err)
-		os.Exit(1)
+		log.Fatal("failed to create migrator",
+			zap.Error(err),
+		)
 	}
-	defer func() {
-		sourceErr, dbErr := m.Close()
-		if sourceErr != nil {
-			fmt.Fprintf(os.Stderr, "Failed to close source: %v\n", sourceErr)
-		}
-		if dbErr != nil {
-			fmt.Fprintf(os.Stderr, "Failed to close database: %v\n", dbErr)
-		}
-	}()
+	m.Log = migrateLogger{log: log}
 
 	// Run migrations
 	switch direction {
@@ -63,19 +89,33 @@ func main() {
 		} else {
 			err = m.Down()
 		}
-	default:
-		fmt.Fprintf(os.Stderr, "Invalid direction: %s (must be 'up' or 'down')\n", direction)
-		os.Exit(1)
+	}
+
+	sourceErr, dbErr := m.Close()
+	if sourceErr != nil {
+		log.Error("failed to close migration source",
+			zap.Error(sourceErr),
+		)
+	}
+	if dbErr != nil {
+		log.Error("failed to close database",
+			zap.Error(dbErr),
+		)
 	}
 
 	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		fmt.Fprintf(os.Stderr, "Migration failed: %v\n", err)
-		os.Exit(1)
+		log.Fatal("migration failed",
+			zap.String("direction", direction),
+			zap.Error(err),
+		)
 	}
 
 	if errors.Is(err, migrate.ErrNoChange) {
-		fmt.Println("No migrations to apply")
+		log.Info("no migrations to apply")
 	} else {
-		fmt.Printf("Successfully applied migrations (%s)\n", direction)
+		log.Info("successfully applied migrations",
+			zap.String("direction", direction),
+			zap.Int("steps", steps),
+		)
 	}
 }
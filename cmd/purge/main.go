@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	_ "github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/Thrun12/golang-assignment/internal/config"
+	store "github.com/Thrun12/golang-assignment/internal/db"
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+	"github.com/Thrun12/golang-assignment/internal/logging"
+)
+
+// Audit events of retention purges are attributed to this actor and method
+const (
+	purgeActor  = "system:purge"
+	purgeMethod = "cmd/purge"
+)
+
+func main() {
+	var (
+		dryRun    bool
+		retention time.Duration
+	)
+
+	flag.BoolVar(&dryRun, "dry-run", false, "Report how many applicants would be purged without deleting them")
+	flag.DurationVar(&retention, "retention", 0, "Purge applicants deleted longer ago than this (default: DELETED_APPLICANT_RETENTION)")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	if retention <= 0 {
+		retention = cfg.DeletedApplicantRetention
+	}
+
+	// Initialize logger
+	log, _, err := logging.New(cfg.GetLoggingConfig())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer func() {
+		_ = log.Sync()
+	}()
+
+	// Connect to database
+	ctx := context.Background()
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal("failed to connect to database",
+			zap.Error(err),
+		)
+	}
+	defer db.Close()
+
+	// Test database connection
+	if err := db.PingContext(ctx); err != nil {
+		log.Fatal("failed to ping database",
+			zap.Error(err),
+		)
+	}
+
+	queries := store.NewStore(db)
+	deletedBefore := time.Now().Add(-retention)
+
+	log.Info("purging deleted applicants",
+		zap.Duration("retention", retention),
+		zap.Time("deleted_before", deletedBefore),
+		zap.Bool("dry_run", dryRun),
+	)
+
+	if dryRun {
+		count, err := queries.CountPurgeableApplicants(ctx, deletedBefore)
+		if err != nil {
+			log.Fatal("failed to count purgeable applicants",
+				zap.Error(err),
+			)
+		}
+		fmt.Printf("🔍 Dry run: %d applicants deleted before %s would be purged\n", count, deletedBefore.Format(time.RFC3339))
+		return
+	}
+
+	purged, err := queries.PurgeDeletedApplicants(ctx, sqlc.PurgeDeletedApplicantsParams{
+		DeletedBefore: deletedBefore,
+		Actor:         purgeActor,
+		Method:        purgeMethod,
+	})
+	if err != nil {
+		log.Fatal("failed to purge deleted applicants",
+			zap.Error(err),
+		)
+	}
+
+	fmt.Printf("✅ Purged %d applicants deleted before %s\n", purged, deletedBefore.Format(time.RFC3339))
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	_ "github.com/lib/pq"
+	"go.uber.org/zap"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/config"
+	store "github.com/Thrun12/golang-assignment/internal/db"
+	"github.com/Thrun12/golang-assignment/internal/logging"
+	"github.com/Thrun12/golang-assignment/internal/scoring"
+	"github.com/Thrun12/golang-assignment/internal/service"
+)
+
+func main() {
+	var (
+		dryRun    bool
+		batchSize int
+		stateFile string
+		reset     bool
+	)
+
+	flag.BoolVar(&dryRun, "dry-run", false, "Report score changes without writing them")
+	flag.IntVar(&batchSize, "batch-size", 100, "Number of applicants recomputed per transaction")
+	flag.StringVar(&stateFile, "state-file", ".rescore-state", "File recording the last processed applicant ID, used to resume")
+	flag.BoolVar(&reset, "reset", false, "Ignore the state file and start from the first applicant")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Initialize logger
+	log, _, err := logging.New(cfg.GetLoggingConfig())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer func() {
+		_ = log.Sync()
+	}()
+
+	// Stop between batches on interrupt so the state file stays consistent
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Connect to database
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal("failed to connect to database",
+			zap.Error(err),
+		)
+	}
+	defer db.Close()
+
+	// Test database connection
+	if err := db.PingContext(ctx); err != nil {
+		log.Fatal("failed to ping database",
+			zap.Error(err),
+		)
+	}
+
+	// Load scoring models
+	scoringModels, err := scoring.LoadRegistry(cfg.ScoringModelsPath)
+	if err != nil {
+		log.Fatal("failed to load scoring models",
+			zap.Error(err),
+		)
+	}
+
+	// Initialize queries and service
+	queries := store.NewStore(db)
+	applicantService := service.NewApplicantService(queries, scoringModels, nil, log)
+
+	// Resume from the last checkpoint unless asked to start over
+	afterID := int64(0)
+	if !reset && !dryRun {
+		afterID, err = readState(stateFile)
+		if err != nil {
+			log.Fatal("failed to read state file",
+				zap.String("state_file", stateFile),
+				zap.Error(err),
+			)
+		}
+		if afterID > 0 {
+			log.Info("resuming score recomputation", zap.Int64("after_id", afterID))
+		}
+	}
+
+	var scanned, changed int32
+	for {
+		resp, err := applicantService.RecomputeScores(ctx, &applicantsv1.RecomputeScoresRequest{
+			DryRun:        dryRun,
+			BatchSize:     int32(batchSize),
+			AfterId:       afterID,
+			MaxApplicants: int32(batchSize),
+		})
+		if err != nil {
+			log.Fatal("failed to recompute scores",
+				zap.Int64("after_id", afterID),
+				zap.Error(err),
+			)
+		}
+
+		for _, c := range resp.Changes {
+			fmt.Printf("%6d  %-30s  %6.2f -> %6.2f  (%s -> %s)\n",
+				c.ApplicantId, c.Name, c.OldScore, c.NewScore,
+				c.OldScoringModelVersion, c.NewScoringModelVersion,
+			)
+		}
+
+		scanned += resp.Scanned
+		changed += resp.Changed
+		afterID = resp.LastId
+
+		// Record progress after every committed batch
+		if !dryRun {
+			if err := writeState(stateFile, afterID); err != nil {
+				log.Fatal("failed to write state file",
+					zap.String("state_file", stateFile),
+					zap.Error(err),
+				)
+			}
+		}
+
+		if resp.Done {
+			break
+		}
+	}
+
+	// A completed run needs no checkpoint
+	if !dryRun {
+		if err := os.Remove(stateFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Warn("failed to remove state file", zap.Error(err))
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("\n🔍 Dry run: %d of %d applicants would change\n", changed, scanned)
+	} else {
+		fmt.Printf("\n✅ Recomputed scores: %d of %d applicants changed\n", changed, scanned)
+	}
+}
+
+// readState returns the applicant ID recorded in the state file, or 0 if there is none
+func readState(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// writeState records the last processed applicant ID
+func writeState(path string, lastID int64) error {
+	return os.WriteFile(path, []byte(strconv.FormatInt(lastID, 10)+"\n"), 0o644)
+}
@@ -12,7 +12,9 @@ import (
 
 	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
 	"github.com/Thrun12/golang-assignment/internal/config"
-	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+	store "github.com/Thrun12/golang-assignment/internal/db"
+	"github.com/Thrun12/golang-assignment/internal/logging"
+	"github.com/Thrun12/golang-assignment/internal/scoring"
 	"github.com/Thrun12/golang-assignment/internal/service"
 )
 
@@ -127,6 +129,14 @@ var seedApplicants = []*applicantsv1.CreateApplicantRequest{
 	},
 }
 
+// seedStatusPath is the workflow route seeded applicants take from APPLIED to
+// the status they are seeded with
+var seedStatusPath = []applicantsv1.ApplicantStatus{
+	applicantsv1.ApplicantStatus_APPLICANT_STATUS_REVIEWING,
+	applicantsv1.ApplicantStatus_APPLICANT_STATUS_INTERVIEWED,
+	applicantsv1.ApplicantStatus_APPLICANT_STATUS_OBVIOUSLY_THE_BEST,
+}
+
 func main() {
 	var clearFirst bool
 	flag.BoolVar(&clearFirst, "clear", false, "Clear existing applicants before seeding")
@@ -140,7 +150,7 @@ func main() {
 	}
 
 	// Initialize logger
-	log, err := zap.NewDevelopment()
+	log, _, err := logging.New(cfg.GetLoggingConfig())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
 		os.Exit(1)
@@ -168,9 +178,17 @@ func main() {
 		)
 	}
 
+	// Load scoring models
+	scoringModels, err := scoring.LoadRegistry(cfg.ScoringModelsPath)
+	if err != nil {
+		log.Fatal("failed to load scoring models",
+			zap.Error(err),
+		)
+	}
+
 	// Initialize queries and service
-	queries := sqlc.New(db)
-	applicantService := service.NewApplicantService(queries, log)
+	queries := store.NewStore(db)
+	applicantService := service.NewApplicantService(queries, scoringModels, nil, log)
 
 	// Clear existing applicants if requested
 	if clearFirst {
@@ -187,6 +205,10 @@ func main() {
 	log.Info("seeding applicants", zap.Int("count", len(seedApplicants)))
 
 	for i, input := range seedApplicants {
+		// New applicants start as APPLIED and are moved on through the workflow
+		target := input.Status
+		input.Status = applicantsv1.ApplicantStatus_APPLICANT_STATUS_APPLIED
+
 		applicant, err := applicantService.CreateApplicant(ctx, input)
 		if err != nil {
 			log.Error("failed to create applicant",
@@ -197,6 +219,26 @@ func main() {
 			continue
 		}
 
+		for _, step := range seedStatusPath {
+			if applicant.Applicant.Status == target {
+				break
+			}
+			transitioned, err := applicantService.TransitionApplicantStatus(ctx, &applicantsv1.TransitionApplicantStatusRequest{
+				Id:     applicant.Applicant.Id,
+				Status: step,
+				Reason: "seed data",
+			})
+			if err != nil {
+				log.Error("failed to transition applicant",
+					zap.String("name", input.Name),
+					zap.String("status", step.String()),
+					zap.Error(err),
+				)
+				break
+			}
+			applicant.Applicant = transitioned.Applicant
+		}
+
 		log.Info("created applicant",
 			zap.String("name", applicant.Applicant.Name),
 			zap.String("email", applicant.Applicant.Email),
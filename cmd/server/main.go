@@ -11,17 +11,28 @@ import (
 	"syscall"
 	"time"
 
+	"buf.build/go/protovalidate"
 	_ "github.com/lib/pq"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
 	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/auth"
 	"github.com/Thrun12/golang-assignment/internal/config"
-	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+	store "github.com/Thrun12/golang-assignment/internal/db"
+	"github.com/Thrun12/golang-assignment/internal/logging"
+	"github.com/Thrun12/golang-assignment/internal/logging/calllog"
+	"github.com/Thrun12/golang-assignment/internal/metrics"
 	"github.com/Thrun12/golang-assignment/internal/middleware"
+	"github.com/Thrun12/golang-assignment/internal/ratelimit"
+	"github.com/Thrun12/golang-assignment/internal/rbac"
+	"github.com/Thrun12/golang-assignment/internal/redact"
+	"github.com/Thrun12/golang-assignment/internal/scoring"
 	"github.com/Thrun12/golang-assignment/internal/server"
 	"github.com/Thrun12/golang-assignment/internal/service"
+	"github.com/Thrun12/golang-assignment/internal/tracing"
 )
 
 func main() {
@@ -33,7 +44,7 @@ func main() {
 	}
 
 	// Initialize logger
-	log, err := zap.NewDevelopment()
+	log, logLevel, err := logging.New(cfg.GetLoggingConfig())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
 		os.Exit(1)
@@ -51,6 +62,23 @@ func main() {
 
 	// Connect to database
 	ctx := context.Background()
+
+	// Export traces of gateway requests, RPCs and queries
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:       cfg.TracingExporter,
+		File:           cfg.TracingFile,
+		OTLPEndpoint:   cfg.TracingOTLPEndpoint,
+		OTLPInsecure:   cfg.TracingOTLPInsecure,
+		SampleRatio:    cfg.TracingSampleRatio,
+		ServiceVersion: cfg.ServiceVersion,
+		Environment:    cfg.Environment,
+	})
+	if err != nil {
+		log.Fatal("failed to initialize tracing",
+			zap.Error(err),
+		)
+	}
+
 	db, err := sql.Open("postgres", cfg.DatabaseURL)
 	if err != nil {
 		log.Fatal("failed to connect to database",
@@ -76,16 +104,160 @@ func main() {
 		zap.Int("max_idle_conns", 5),
 	)
 
-	// Initialize queries and service layers
-	queries := sqlc.New(db)
-	applicantService := service.NewApplicantService(queries, log)
+	// Load scoring models
+	scoringModels, err := scoring.LoadRegistry(cfg.ScoringModelsPath)
+	if err != nil {
+		log.Fatal("failed to load scoring models",
+			zap.Error(err),
+		)
+	}
+	log.Info("loaded scoring models",
+		zap.Int("count", len(scoringModels.List())),
+		zap.String("default_version", scoringModels.DefaultVersion()),
+	)
+
+	// Initialize queries
+	queries := store.NewStore(db)
+
+	// Listen for applicant changes to stream to WatchApplicants clients
+	changes, err := store.ListenForChanges(cfg.DatabaseURL, queries, log)
+	if err != nil {
+		log.Fatal("failed to listen for applicant changes",
+			zap.Error(err),
+		)
+	}
+
+	// Initialize service layer
+	applicantService := service.NewApplicantService(queries, scoringModels, changes, log)
+
+	// Request validator enforcing the rules declared in the proto files
+	validator, err := protovalidate.New()
+	if err != nil {
+		log.Fatal("failed to create request validator",
+			zap.Error(err),
+		)
+	}
+
+	// Prometheus metrics for RPCs, gateway requests, the connection pool and
+	// applicants. Applicant metrics are refreshed in the background rather than
+	// queried on every scrape.
+	metricsCtx, metricsCancel := context.WithCancel(ctx)
+	defer metricsCancel()
+
+	var serverMetrics *metrics.Metrics
+	if cfg.MetricsEnabled {
+		serverMetrics = metrics.New()
+		serverMetrics.RegisterDB(db, "applicants")
+		applicantMetrics := metrics.NewApplicantCollector(queries, log)
+		if err := serverMetrics.Register(applicantMetrics); err != nil {
+			log.Fatal("failed to register applicant metrics",
+				zap.Error(err),
+			)
+		}
+		go applicantMetrics.Run(metricsCtx)
+	}
+
+	logPolicy, err := calllog.LoadPolicy(cfg.LogPolicyPath)
+	if err != nil {
+		log.Fatal("failed to load log policy",
+			zap.Error(err),
+		)
+	}
+
+	// Unary and streaming RPCs pass through the same interceptors in the same order
+	var interceptors []grpc.UnaryServerInterceptor
+	var streamInterceptors []grpc.StreamServerInterceptor
+	if serverMetrics != nil {
+		interceptors = append(interceptors, middleware.MetricsInterceptor(serverMetrics))
+		streamInterceptors = append(streamInterceptors, middleware.MetricsStreamInterceptor(serverMetrics))
+	}
+	interceptors = append(interceptors,
+		middleware.RecoveryInterceptor(log),
+		middleware.UnaryServerInterceptor(log, logPolicy),
+	)
+	streamInterceptors = append(streamInterceptors,
+		middleware.RecoveryStreamInterceptor(log),
+		middleware.StreamServerInterceptor(log, logPolicy),
+	)
+
+	// Charge every RPC to the budget of its client address before
+	// authenticating it, so bad credentials are throttled too
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimitEnabled {
+		limitPolicy, err := ratelimit.LoadPolicy(cfg.RateLimitPolicyPath)
+		if err != nil {
+			log.Fatal("failed to load rate limit policy",
+				zap.Error(err),
+			)
+		}
+		limiter = ratelimit.NewLimiter(limitPolicy)
+		interceptors = append(interceptors, middleware.IPRateLimitInterceptor(limiter, log))
+		streamInterceptors = append(streamInterceptors, middleware.IPRateLimitStreamInterceptor(limiter, log))
+	}
+
+	// Require a JWT bearer token or an API key on every RPC
+	if cfg.AuthEnabled {
+		authenticator, err := auth.NewAuthenticator(auth.Config{
+			HS256Secret: cfg.JWTHS256Secret,
+			JWKSFile:    cfg.JWTJWKSFile,
+			Issuer:      cfg.JWTIssuer,
+			Audience:    cfg.JWTAudience,
+		})
+		if err != nil {
+			log.Fatal("failed to initialize authentication",
+				zap.Error(err),
+			)
+		}
+		apiKeys := auth.NewAPIKeyAuthenticator(queries, log)
+		interceptors = append(interceptors, middleware.AuthInterceptor(authenticator, apiKeys, log))
+		streamInterceptors = append(streamInterceptors, middleware.AuthStreamInterceptor(authenticator, apiKeys, log))
+	} else {
+		log.Warn("authentication is disabled, all RPCs are public")
+	}
+
+	// Charge every RPC to its caller's rate limit budget
+	if limiter != nil {
+		interceptors = append(interceptors, middleware.RateLimitInterceptor(limiter, log))
+		streamInterceptors = append(streamInterceptors, middleware.RateLimitStreamInterceptor(limiter, log))
+	}
+
+	// Enforce the access policy for every RPC
+	if cfg.RBACEnabled {
+		policy, err := rbac.LoadPolicy(cfg.RBACPolicyPath)
+		if err != nil {
+			log.Fatal("failed to load access policy",
+				zap.Error(err),
+			)
+		}
+		interceptors = append(interceptors, middleware.RBACInterceptor(policy, cfg.RBACTrustRoleHeader, log))
+		streamInterceptors = append(streamInterceptors, middleware.RBACStreamInterceptor(policy, cfg.RBACTrustRoleHeader, log))
+		if cfg.RBACTrustRoleHeader && len(cfg.GetRBACTrustedProxies()) == 0 {
+			log.Warn("RBAC_TRUSTED_PROXIES is empty, the REST gateway drops role headers from every caller")
+		}
+	} else {
+		log.Warn("access control is disabled, every caller may call every RPC")
+	}
+
+	// Hide applicant fields the caller's role may not see
+	if cfg.RedactionEnabled {
+		fieldPolicy, err := redact.LoadPolicy(cfg.RedactionPolicyPath)
+		if err != nil {
+			log.Fatal("failed to load field policy",
+				zap.Error(err),
+			)
+		}
+		interceptors = append(interceptors, middleware.RedactionInterceptor(fieldPolicy))
+		streamInterceptors = append(streamInterceptors, middleware.RedactionStreamInterceptor(fieldPolicy))
+	}
+
+	interceptors = append(interceptors, middleware.ValidationInterceptor(validator))
+	streamInterceptors = append(streamInterceptors, middleware.ValidationStreamInterceptor(validator))
 
 	// Create gRPC server
 	grpcServer := grpc.NewServer(
-		grpc.ChainUnaryInterceptor(
-			middleware.RecoveryInterceptor(log),
-			middleware.UnaryServerInterceptor(log),
-		),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(interceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
 	)
 
 	// Register gRPC services - service layer implements the gRPC interface directly
@@ -119,7 +291,7 @@ func main() {
 	defer gatewayCancel()
 
 	grpcAddress := fmt.Sprintf("localhost:%d", cfg.GRPCPort)
-	gatewayHandler, err := server.NewGatewayServer(gatewayCtx, grpcAddress, cfg.GetCORSOrigins(), db, log)
+	gatewayHandler, err := server.NewGatewayServer(gatewayCtx, grpcAddress, cfg.GetCORSOrigins(), cfg.GetRBACTrustedProxies(), db, serverMetrics, log)
 	if err != nil {
 		log.Fatal("failed to create gateway server",
 			zap.Error(err),
@@ -152,6 +324,28 @@ func main() {
 		}
 	}()
 
+	// Start admin server, which changes the log level at runtime
+	var adminServer *http.Server
+	if cfg.AdminAddress != "" {
+		adminServer = &http.Server{
+			Addr:         cfg.AdminAddress,
+			Handler:      server.NewAdminHandler(logLevel),
+			ReadTimeout:  15 * time.Second,
+			WriteTimeout: 15 * time.Second,
+		}
+
+		go func() {
+			log.Info("starting admin server",
+				zap.String("address", cfg.AdminAddress),
+			)
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatal("failed to serve admin HTTP",
+					zap.Error(err),
+				)
+			}
+		}()
+	}
+
 	// Wait for interrupt signal to gracefully shutdown the servers
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -163,13 +357,30 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
 
+	// End watch streams, which would otherwise keep both servers from stopping
+	if err := changes.Close(); err != nil {
+		log.Error("change listener shutdown error", zap.Error(err))
+	}
+
 	// Shutdown HTTP server
 	if err := httpServer.Shutdown(shutdownCtx); err != nil {
 		log.Error("HTTP server shutdown error", zap.Error(err))
 	}
 
+	// Shutdown admin server
+	if adminServer != nil {
+		if err := adminServer.Shutdown(shutdownCtx); err != nil {
+			log.Error("admin server shutdown error", zap.Error(err))
+		}
+	}
+
 	// Shutdown gRPC server
 	grpcServer.GracefulStop()
 
+	// Flush pending spans
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Error("tracing shutdown error", zap.Error(err))
+	}
+
 	log.Info("servers stopped")
 }
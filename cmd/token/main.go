@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/Thrun12/golang-assignment/internal/auth"
+	"github.com/Thrun12/golang-assignment/internal/config"
+)
+
+// token prints an HS256 bearer token signed with JWT_HS256_SECRET, for local
+// development and testing. Production tokens come from the identity provider.
+func main() {
+	var (
+		subject string
+		roles   string
+		agency  string
+		ttl     time.Duration
+	)
+
+	flag.StringVar(&subject, "subject", "dev@example.com", "Subject (sub claim) of the token")
+	flag.StringVar(&roles, "roles", "", "Comma-separated roles granted by the token")
+	flag.StringVar(&agency, "agency", "", "Recruiting agency the subject works for")
+	flag.DurationVar(&ttl, "ttl", time.Hour, "How long the token is valid")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	if cfg.JWTHS256Secret == "" {
+		fmt.Fprintln(os.Stderr, "JWT_HS256_SECRET is not set")
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			Issuer:    cfg.JWTIssuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		Agency: agency,
+	}
+	if cfg.JWTAudience != "" {
+		claims.Audience = jwt.ClaimStrings{cfg.JWTAudience}
+	}
+	for _, role := range strings.Split(roles, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			claims.Roles = append(claims.Roles, role)
+		}
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.JWTHS256Secret))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to sign token: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(token)
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+)
+
+// fakeAPIKeyStore holds active API keys by hash and records touched keys
+type fakeAPIKeyStore struct {
+	keys    map[string]sqlc.ApiKey
+	err     error
+	touched []int64
+}
+
+func (f *fakeAPIKeyStore) GetActiveApiKeyByHash(ctx context.Context, keyHash string) (sqlc.ApiKey, error) {
+	if f.err != nil {
+		return sqlc.ApiKey{}, f.err
+	}
+	key, ok := f.keys[keyHash]
+	if !ok {
+		return sqlc.ApiKey{}, sql.ErrNoRows
+	}
+	return key, nil
+}
+
+func (f *fakeAPIKeyStore) TouchApiKey(ctx context.Context, id int64) error {
+	f.touched = append(f.touched, id)
+	return nil
+}
+
+func TestGenerateAPIKey(t *testing.T) {
+	first, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	second, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if first.Key == second.Key {
+		t.Error("Expected unique keys")
+	}
+	if !strings.HasPrefix(first.Key, apiKeyPrefix) || !strings.HasPrefix(first.Key, first.Prefix) {
+		t.Errorf("Expected key %q to start with %q", first.Key, first.Prefix)
+	}
+	if first.Hash != HashAPIKey(first.Key) || len(first.Hash) != 64 {
+		t.Errorf("Expected hex SHA-256 hash, got %q", first.Hash)
+	}
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	generated, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	store := &fakeAPIKeyStore{keys: map[string]sqlc.ApiKey{
+		generated.Hash: {ID: 3, Owner: "ats-sync", Scopes: []string{"recruiter"}},
+	}}
+	authenticator := NewAPIKeyAuthenticator(store, zap.NewNop())
+	ctx := context.Background()
+
+	principal, err := authenticator.Authenticate(ctx, generated.Key)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if principal.Subject != "ats-sync" || principal.APIKeyID != 3 || len(principal.Roles) != 1 || principal.Roles[0] != "recruiter" {
+		t.Errorf("Unexpected principal: %+v", principal)
+	}
+	if len(store.touched) != 1 || store.touched[0] != 3 {
+		t.Errorf("Expected key use to be recorded, got %v", store.touched)
+	}
+
+	// Unknown, revoked and expired keys are not returned by the store
+	if _, err := authenticator.Authenticate(ctx, apiKeyPrefix+"unknown"); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey, got %v", err)
+	}
+	if _, err := authenticator.Authenticate(ctx, "not-an-api-key"); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey, got %v", err)
+	}
+
+	store.err = errors.New("connection refused")
+	if _, err := authenticator.Authenticate(ctx, generated.Key); err == nil || errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected lookup error, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MicahParks/keyfunc/v3"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minSecretLength is the shortest accepted HS256 secret in bytes (RFC 7518 section 3.2)
+const minSecretLength = 32
+
+// Claims are the JWT claims understood by the service
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles  []string `json:"roles,omitempty"`
+	Agency string   `json:"agency,omitempty"`
+}
+
+// Config configures which tokens an Authenticator accepts. At least one of
+// HS256Secret and JWKSFile must be set.
+type Config struct {
+	// Shared secret for HS256 signed tokens
+	HS256Secret string
+
+	// Path to a JWKS file holding the public keys for RS256 signed tokens
+	JWKSFile string
+
+	// Required "iss" and "aud" claims (optional)
+	Issuer   string
+	Audience string
+}
+
+// Authenticator verifies JWT bearer tokens
+type Authenticator struct {
+	parser  *jwt.Parser
+	keyfunc jwt.Keyfunc
+}
+
+// NewAuthenticator creates an Authenticator from cfg, loading the JWKS file if set
+func NewAuthenticator(cfg Config) (*Authenticator, error) {
+	var (
+		methods []string
+		secret  []byte
+		jwks    keyfunc.Keyfunc
+	)
+
+	if cfg.HS256Secret != "" {
+		if len(cfg.HS256Secret) < minSecretLength {
+			return nil, fmt.Errorf("HS256 secret must be at least %d bytes", minSecretLength)
+		}
+		secret = []byte(cfg.HS256Secret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if cfg.JWKSFile != "" {
+		data, err := os.ReadFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+		}
+		jwks, err = keyfunc.NewJWKSetJSON(json.RawMessage(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWKS file: %w", err)
+		}
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	if len(methods) == 0 {
+		return nil, errors.New("no JWT keys configured, set an HS256 secret or a JWKS file")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	return &Authenticator{
+		parser: jwt.NewParser(opts...),
+		keyfunc: func(token *jwt.Token) (interface{}, error) {
+			// WithValidMethods guarantees the method was configured
+			if token.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+				return secret, nil
+			}
+			return jwks.Keyfunc(token)
+		},
+	}, nil
+}
+
+// Authenticate verifies token and returns the principal it was issued to
+func (a *Authenticator) Authenticate(token string) (*Principal, error) {
+	claims := &Claims{}
+	if _, err := a.parser.ParseWithClaims(token, claims, a.keyfunc); err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+
+	return &Principal{
+		Subject: claims.Subject,
+		Roles:   claims.Roles,
+		Agency:  claims.Agency,
+	}, nil
+}
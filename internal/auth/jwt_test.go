@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret-that-is-at-least-32-bytes"
+
+func signHS256(t *testing.T, secret string, claims Claims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
+
+func validClaims() Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "recruiter@example.com",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Roles: []string{"recruiter"},
+	}
+}
+
+// writeJWKS writes a JWKS file containing the public half of key
+func writeJWKS(t *testing.T, kid string, key *rsa.PrivateKey) string {
+	t.Helper()
+	jwks := map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": kid,
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	}
+	data, err := json.Marshal(jwks)
+	if err != nil {
+		t.Fatalf("failed to marshal JWKS: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write JWKS: %v", err)
+	}
+	return path
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	if _, err := NewAuthenticator(Config{}); err == nil {
+		t.Error("Expected error without any keys configured")
+	}
+	if _, err := NewAuthenticator(Config{HS256Secret: "too-short"}); err == nil {
+		t.Error("Expected error for a short HS256 secret")
+	}
+	if _, err := NewAuthenticator(Config{JWKSFile: filepath.Join(t.TempDir(), "missing.json")}); err == nil {
+		t.Error("Expected error for a missing JWKS file")
+	}
+}
+
+func TestAuthenticate_HS256(t *testing.T) {
+	authenticator, err := NewAuthenticator(Config{HS256Secret: testSecret, Issuer: "https://auth.example.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	withIssuer := validClaims()
+	withIssuer.Issuer = "https://auth.example.com"
+
+	expired := withIssuer
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+	noExpiry := withIssuer
+	noExpiry.ExpiresAt = nil
+
+	wrongIssuer := validClaims()
+	wrongIssuer.Issuer = "https://evil.example.com"
+
+	noSubject := withIssuer
+	noSubject.Subject = ""
+
+	tests := []struct {
+		name      string
+		token     string
+		expectErr bool
+	}{
+		{name: "Valid token", token: signHS256(t, testSecret, withIssuer)},
+		{name: "Wrong secret", token: signHS256(t, "another-secret-that-is-32-bytes-long", withIssuer), expectErr: true},
+		{name: "Expired", token: signHS256(t, testSecret, expired), expectErr: true},
+		{name: "No expiry", token: signHS256(t, testSecret, noExpiry), expectErr: true},
+		{name: "Wrong issuer", token: signHS256(t, testSecret, wrongIssuer), expectErr: true},
+		{name: "No subject", token: signHS256(t, testSecret, noSubject), expectErr: true},
+		{name: "Malformed", token: "not-a-jwt", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := authenticator.Authenticate(tt.token)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if principal.Subject != "recruiter@example.com" {
+				t.Errorf("Expected subject 'recruiter@example.com', got '%s'", principal.Subject)
+			}
+			if len(principal.Roles) != 1 || principal.Roles[0] != "recruiter" {
+				t.Errorf("Expected roles [recruiter], got %v", principal.Roles)
+			}
+		})
+	}
+}
+
+func TestAuthenticate_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	authenticator, err := NewAuthenticator(Config{JWKSFile: writeJWKS(t, "key-1", key)})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	sign := func(kid string, key *rsa.PrivateKey) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims())
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return signed
+	}
+
+	if _, err := authenticator.Authenticate(sign("key-1", key)); err != nil {
+		t.Errorf("Expected token signed with the JWKS key to be accepted, got: %v", err)
+	}
+	if _, err := authenticator.Authenticate(sign("key-1", otherKey)); err == nil {
+		t.Error("Expected token signed with another key to be rejected")
+	}
+	if _, err := authenticator.Authenticate(sign("unknown", key)); err == nil {
+		t.Error("Expected token with an unknown kid to be rejected")
+	}
+
+	// HS256 is not accepted when only a JWKS file is configured
+	if _, err := authenticator.Authenticate(signHS256(t, testSecret, validClaims())); err == nil {
+		t.Error("Expected HS256 token to be rejected")
+	}
+}
@@ -0,0 +1,33 @@
+package auth
+
+import "context"
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	// Subject identifies the caller (the token's "sub" claim)
+	Subject string
+
+	// Roles granted to the caller (the token's "roles" claim)
+	Roles []string
+
+	// Agency the caller works for (the token's "agency" claim), empty for
+	// internal users
+	Agency string
+
+	// APIKeyID is the API key the caller authenticated with, 0 for tokens
+	APIKeyID int64
+}
+
+// principalKey is the context key for the request principal
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying principal
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal stored in ctx, if any
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
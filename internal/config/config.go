@@ -2,20 +2,83 @@ package config
 
 import (
 	"fmt"
+	"net/netip"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/Thrun12/golang-assignment/internal/logging"
 )
 
 // Config holds all application configuration
 type Config struct {
-	Environment     string `mapstructure:"ENVIRONMENT"`
-	ServiceVersion  string `mapstructure:"SERVICE_VERSION"`
-	DatabaseURL     string `mapstructure:"DATABASE_URL"`
-	ServerPort      int    `mapstructure:"SERVER_PORT"`
-	GRPCPort        int    `mapstructure:"GRPC_PORT"`
-	CORSOrigins     string `mapstructure:"CORS_ORIGINS"`
-	MigrationPath   string `mapstructure:"MIGRATION_PATH"`
+	Environment       string `mapstructure:"ENVIRONMENT"`
+	ServiceVersion    string `mapstructure:"SERVICE_VERSION"`
+	DatabaseURL       string `mapstructure:"DATABASE_URL"`
+	ServerPort        int    `mapstructure:"SERVER_PORT"`
+	GRPCPort          int    `mapstructure:"GRPC_PORT"`
+	CORSOrigins       string `mapstructure:"CORS_ORIGINS"`
+	MigrationPath     string `mapstructure:"MIGRATION_PATH"`
+	ScoringModelsPath string `mapstructure:"SCORING_MODELS_PATH"`
+
+	// Logging. LogFormat is json or console; LogStacktraceLevel is the minimum
+	// level log lines carry a stack trace at.
+	LogLevel           string `mapstructure:"LOG_LEVEL"`
+	LogFormat          string `mapstructure:"LOG_FORMAT"`
+	LogSampling        bool   `mapstructure:"LOG_SAMPLING"`
+	LogCaller          bool   `mapstructure:"LOG_CALLER"`
+	LogStacktraceLevel string `mapstructure:"LOG_STACKTRACE_LEVEL"`
+
+	// Per-method log levels, payload logging and slow request thresholds.
+	// LogPolicyPath points to a policy file (the built-in policy when empty).
+	LogPolicyPath string `mapstructure:"LOG_POLICY_PATH"`
+
+	// Address of the admin HTTP server, which changes the log level at runtime.
+	// Keep it on loopback or an internal network; empty disables it.
+	AdminAddress string `mapstructure:"ADMIN_ADDRESS"`
+
+	// How long soft-deleted applicants are kept before the purge command removes them
+	DeletedApplicantRetention time.Duration `mapstructure:"DELETED_APPLICANT_RETENTION"`
+
+	// JWT authentication. When enabled, every RPC requires a bearer token signed
+	// with JWTHS256Secret (HS256) or a key from JWTJWKSFile (RS256).
+	AuthEnabled    bool   `mapstructure:"AUTH_ENABLED"`
+	JWTHS256Secret string `mapstructure:"JWT_HS256_SECRET"`
+	JWTJWKSFile    string `mapstructure:"JWT_JWKS_FILE"`
+	JWTIssuer      string `mapstructure:"JWT_ISSUER"`
+	JWTAudience    string `mapstructure:"JWT_AUDIENCE"`
+
+	// Role-based access control. RBACPolicyPath points to a policy file (the
+	// built-in policy when empty); RBACTrustRoleHeader accepts caller roles from
+	// the x-user-roles header when no token is presented. The REST gateway only
+	// forwards that header from RBACTrustedProxies, comma-separated addresses or
+	// CIDR ranges, and drops it from everyone else.
+	RBACEnabled         bool   `mapstructure:"RBAC_ENABLED"`
+	RBACPolicyPath      string `mapstructure:"RBAC_POLICY_PATH"`
+	RBACTrustRoleHeader bool   `mapstructure:"RBAC_TRUST_ROLE_HEADER"`
+	RBACTrustedProxies  string `mapstructure:"RBAC_TRUSTED_PROXIES"`
+
+	// Field-level redaction of applicant responses by caller role.
+	// RedactionPolicyPath points to a field policy file (the built-in policy when empty).
+	RedactionEnabled    bool   `mapstructure:"REDACTION_ENABLED"`
+	RedactionPolicyPath string `mapstructure:"REDACTION_POLICY_PATH"`
+
+	// Per-client rate limiting. RateLimitPolicyPath points to a policy file with
+	// per-method limits (the built-in policy when empty).
+	RateLimitEnabled    bool   `mapstructure:"RATE_LIMIT_ENABLED"`
+	RateLimitPolicyPath string `mapstructure:"RATE_LIMIT_POLICY_PATH"`
+
+	// Prometheus metrics served on /metrics of the HTTP port
+	MetricsEnabled bool `mapstructure:"METRICS_ENABLED"`
+
+	// OpenTelemetry tracing. TracingExporter is none, stdout, file (JSON lines
+	// written to TracingFile) or otlp (OTLP/gRPC to TracingOTLPEndpoint).
+	TracingExporter     string  `mapstructure:"TRACING_EXPORTER"`
+	TracingFile         string  `mapstructure:"TRACING_FILE"`
+	TracingOTLPEndpoint string  `mapstructure:"TRACING_OTLP_ENDPOINT"`
+	TracingOTLPInsecure bool    `mapstructure:"TRACING_OTLP_INSECURE"`
+	TracingSampleRatio  float64 `mapstructure:"TRACING_SAMPLE_RATIO"`
 }
 
 // Load loads configuration from environment variables and .env file
@@ -60,6 +123,34 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("GRPC_PORT", 9090)
 	v.SetDefault("CORS_ORIGINS", "*")
 	v.SetDefault("MIGRATION_PATH", "internal/db/migrations")
+	v.SetDefault("SCORING_MODELS_PATH", "")
+	v.SetDefault("LOG_LEVEL", "info")
+	v.SetDefault("LOG_FORMAT", "json")
+	v.SetDefault("LOG_SAMPLING", false)
+	v.SetDefault("LOG_CALLER", true)
+	v.SetDefault("LOG_STACKTRACE_LEVEL", "error")
+	v.SetDefault("LOG_POLICY_PATH", "")
+	v.SetDefault("ADMIN_ADDRESS", "localhost:8081")
+	v.SetDefault("DELETED_APPLICANT_RETENTION", "720h")
+	v.SetDefault("AUTH_ENABLED", true)
+	v.SetDefault("JWT_HS256_SECRET", "")
+	v.SetDefault("JWT_JWKS_FILE", "")
+	v.SetDefault("JWT_ISSUER", "")
+	v.SetDefault("JWT_AUDIENCE", "")
+	v.SetDefault("RBAC_ENABLED", true)
+	v.SetDefault("RBAC_POLICY_PATH", "")
+	v.SetDefault("RBAC_TRUST_ROLE_HEADER", false)
+	v.SetDefault("RBAC_TRUSTED_PROXIES", "")
+	v.SetDefault("REDACTION_ENABLED", true)
+	v.SetDefault("REDACTION_POLICY_PATH", "")
+	v.SetDefault("RATE_LIMIT_ENABLED", true)
+	v.SetDefault("RATE_LIMIT_POLICY_PATH", "")
+	v.SetDefault("METRICS_ENABLED", true)
+	v.SetDefault("TRACING_EXPORTER", "none")
+	v.SetDefault("TRACING_FILE", "traces.json")
+	v.SetDefault("TRACING_OTLP_ENDPOINT", "localhost:4317")
+	v.SetDefault("TRACING_OTLP_INSECURE", true)
+	v.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
 }
 
 // Validate validates the configuration
@@ -76,6 +167,30 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("GRPC_PORT must be between 1 and 65535")
 	}
 
+	if c.DeletedApplicantRetention <= 0 {
+		return fmt.Errorf("DELETED_APPLICANT_RETENTION must be positive")
+	}
+
+	switch c.LogFormat {
+	case "json", "console":
+	default:
+		return fmt.Errorf("LOG_FORMAT must be json or console")
+	}
+
+	switch c.TracingExporter {
+	case "none", "stdout", "file", "otlp":
+	default:
+		return fmt.Errorf("TRACING_EXPORTER must be one of none, stdout, file or otlp")
+	}
+
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		return fmt.Errorf("TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+
+	if _, err := parseTrustedProxies(c.RBACTrustedProxies); err != nil {
+		return fmt.Errorf("RBAC_TRUSTED_PROXIES: %w", err)
+	}
+
 	return nil
 }
 
@@ -86,3 +201,49 @@ func (c *Config) GetCORSOrigins() []string {
 	}
 	return strings.Split(c.CORSOrigins, ",")
 }
+
+// GetRBACTrustedProxies returns the addresses the REST gateway accepts the role
+// and agency headers from, or nil unless RBACTrustRoleHeader is set
+func (c *Config) GetRBACTrustedProxies() []netip.Prefix {
+	if !c.RBACTrustRoleHeader {
+		return nil
+	}
+	proxies, _ := parseTrustedProxies(c.RBACTrustedProxies)
+	return proxies
+}
+
+// parseTrustedProxies parses comma-separated addresses and CIDR ranges
+func parseTrustedProxies(value string) ([]netip.Prefix, error) {
+	var proxies []netip.Prefix
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, err
+			}
+			proxies = append(proxies, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return proxies, nil
+}
+
+// GetLoggingConfig returns the logger settings
+func (c *Config) GetLoggingConfig() logging.Config {
+	return logging.Config{
+		Level:           c.LogLevel,
+		Format:          c.LogFormat,
+		Sampling:        c.LogSampling,
+		Caller:          c.LogCaller,
+		StacktraceLevel: c.LogStacktraceLevel,
+	}
+}
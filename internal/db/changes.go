@@ -0,0 +1,363 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+)
+
+// ApplicantChangesChannel is the NOTIFY channel the applicants table announces
+// its changes on
+const ApplicantChangesChannel = "applicant_changes"
+
+// Kinds of ApplicantChange
+const (
+	ChangeCreated = "CREATED"
+	ChangeUpdated = "UPDATED"
+	ChangeDeleted = "DELETED"
+)
+
+const (
+	// subscriptionBuffer is how many applicants with undelivered changes a
+	// subscriber may fall behind by before its subscription is ended
+	subscriptionBuffer = 256
+
+	// changeLoadTimeout bounds loading the applicant of a change
+	changeLoadTimeout = 5 * time.Second
+
+	// listenerPingInterval is how often an idle listener checks its connection
+	listenerPingInterval = 90 * time.Second
+)
+
+// Reasons a subscription ends
+var (
+	ErrChangeFeedClosed  = errors.New("change feed closed")
+	ErrChangesMissed     = errors.New("changes may have been missed while reconnecting to the database")
+	ErrSubscriberTooSlow = errors.New("subscriber fell too far behind")
+	ErrChangeNotLoaded   = errors.New("changed applicant could not be loaded")
+)
+
+// ApplicantLoader is the part of sqlc.Querier the feed loads changed
+// applicants with
+type ApplicantLoader interface {
+	GetApplicant(ctx context.Context, id int64) (sqlc.Applicant, error)
+}
+
+// ApplicantChange is a committed change to an applicant
+type ApplicantChange struct {
+	// Type is ChangeCreated, ChangeUpdated or ChangeDeleted
+	Type        string    `json:"type"`
+	ApplicantID int64     `json:"id"`
+	At          time.Time `json:"at"`
+
+	// Applicant is the applicant as loaded after a created or updated change
+	Applicant *sqlc.Applicant `json:"-"`
+}
+
+// ChangeFeed fans the applicant changes announced by the database out to
+// every subscriber. The applicant of each change is loaded once by the feed,
+// not by every subscriber.
+type ChangeFeed struct {
+	listener   *pq.Listener
+	applicants ApplicantLoader
+	logger     *zap.Logger
+	done       chan struct{}
+
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+// Subscription receives the changes committed after it was created. Changes
+// it has not received yet are merged per applicant, so a burst of changes to
+// the same applicants takes no more room than one change each.
+type Subscription struct {
+	feed    *ChangeFeed
+	changes chan ApplicantChange
+	wake    chan struct{}
+	ended   chan struct{}
+	err     error
+
+	// Undelivered changes by applicant, and the order they arrived in.
+	// Guarded by feed.mu.
+	pending map[int64]ApplicantChange
+	order   []int64
+}
+
+// ListenForChanges opens a dedicated connection that listens on
+// ApplicantChangesChannel and returns the feed of its changes. The connection
+// is re-established if lost.
+func ListenForChanges(databaseURL string, applicants ApplicantLoader, logger *zap.Logger) (*ChangeFeed, error) {
+	listener := pq.NewListener(databaseURL, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			logger.Warn("change listener connection event", zap.Int("event", int(event)), zap.Error(err))
+		}
+	})
+	if err := listener.Listen(ApplicantChangesChannel); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to listen for applicant changes: %w", err)
+	}
+
+	feed := NewChangeFeed(listener.Notify, applicants, logger)
+	feed.listener = listener
+	go feed.ping()
+	return feed, nil
+}
+
+// NewChangeFeed creates a feed of the applicant changes in notifications,
+// loading changed applicants from applicants. A nil notification, which pq
+// sends after reconnecting, ends every subscription with ErrChangesMissed.
+func NewChangeFeed(notifications <-chan *pq.Notification, applicants ApplicantLoader, logger *zap.Logger) *ChangeFeed {
+	f := &ChangeFeed{
+		applicants:  applicants,
+		logger:      logger,
+		done:        make(chan struct{}),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+	go f.run(notifications)
+	return f
+}
+
+// run publishes notifications until the channel is closed
+func (f *ChangeFeed) run(notifications <-chan *pq.Notification) {
+	defer close(f.done)
+
+	for n := range notifications {
+		if n == nil {
+			f.endAll(ErrChangesMissed)
+			continue
+		}
+
+		var change ApplicantChange
+		if err := json.Unmarshal([]byte(n.Extra), &change); err != nil {
+			f.logger.Warn("invalid applicant change notification", zap.String("payload", n.Extra), zap.Error(err))
+			continue
+		}
+		if !f.hasSubscribers() {
+			continue
+		}
+
+		loaded, err := f.load(&change)
+		if err != nil {
+			f.logger.Error("failed to load changed applicant", zap.Int64("applicant_id", change.ApplicantID), zap.Error(err))
+			f.endAll(ErrChangeNotLoaded)
+			continue
+		}
+		if loaded {
+			f.publish(change)
+		}
+	}
+	f.endAll(ErrChangeFeedClosed)
+}
+
+// hasSubscribers reports whether anyone receives the changes
+func (f *ChangeFeed) hasSubscribers() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subscribers) > 0
+}
+
+// load sets the applicant of a created or updated change. It reports false if
+// the applicant was deleted before it could be loaded, which is announced by
+// a later change.
+func (f *ChangeFeed) load(change *ApplicantChange) (bool, error) {
+	if change.Type != ChangeCreated && change.Type != ChangeUpdated {
+		return true, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), changeLoadTimeout)
+	defer cancel()
+
+	applicant, err := f.applicants.GetApplicant(ctx, change.ApplicantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	change.Applicant = &applicant
+	return true, nil
+}
+
+// ping checks the listener connection while no notifications arrive
+func (f *ChangeFeed) ping() {
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.done:
+			return
+		case <-ticker.C:
+			if err := f.listener.Ping(); err != nil {
+				f.logger.Debug("change listener ping failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// publish hands change to every subscriber, ending the subscriptions of those
+// that fell too far behind
+func (f *ChangeFeed) publish(change ApplicantChange) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for sub := range f.subscribers {
+		sub.queue(change)
+		if len(sub.order) > subscriptionBuffer {
+			f.end(sub, ErrSubscriberTooSlow)
+		}
+	}
+}
+
+// Subscribe starts receiving changes. The subscription of a closed feed has
+// already ended with ErrChangeFeedClosed.
+func (f *ChangeFeed) Subscribe() *Subscription {
+	sub := &Subscription{
+		feed:    f,
+		changes: make(chan ApplicantChange),
+		wake:    make(chan struct{}, 1),
+		ended:   make(chan struct{}),
+		pending: make(map[int64]ApplicantChange),
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		sub.err = ErrChangeFeedClosed
+		close(sub.ended)
+		close(sub.changes)
+		return sub
+	}
+	f.subscribers[sub] = struct{}{}
+	go sub.deliver()
+	return sub
+}
+
+// Close ends every subscription with ErrChangeFeedClosed and closes the
+// listener connection
+func (f *ChangeFeed) Close() error {
+	f.mu.Lock()
+	f.closed = true
+	f.mu.Unlock()
+	f.endAll(ErrChangeFeedClosed)
+
+	if f.listener == nil {
+		return nil
+	}
+	err := f.listener.Close()
+	<-f.done
+	return err
+}
+
+// endAll ends every subscription with err
+func (f *ChangeFeed) endAll(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for sub := range f.subscribers {
+		f.end(sub, err)
+	}
+}
+
+// end removes sub, drops its undelivered changes and closes its channel.
+// Must hold f.mu.
+func (f *ChangeFeed) end(sub *Subscription, err error) {
+	if _, ok := f.subscribers[sub]; !ok {
+		return
+	}
+	delete(f.subscribers, sub)
+	sub.err = err
+	sub.pending = nil
+	sub.order = nil
+	close(sub.ended)
+}
+
+// queue adds change to the undelivered changes of s, merging it into an
+// undelivered change of the same applicant. Must hold feed.mu.
+func (s *Subscription) queue(change ApplicantChange) {
+	if prev, ok := s.pending[change.ApplicantID]; ok {
+		// The subscriber has not seen the applicant created yet
+		if prev.Type == ChangeCreated && change.Type == ChangeUpdated {
+			change.Type = ChangeCreated
+		}
+		s.pending[change.ApplicantID] = change
+		return
+	}
+
+	s.pending[change.ApplicantID] = change
+	s.order = append(s.order, change.ApplicantID)
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// deliver sends the undelivered changes of s in order until s ends, then
+// closes its channel
+func (s *Subscription) deliver() {
+	defer close(s.changes)
+
+	for {
+		change, ok := s.next()
+		if !ok {
+			return
+		}
+		select {
+		case s.changes <- change:
+		case <-s.ended:
+			return
+		}
+	}
+}
+
+// next waits for the oldest undelivered change and removes it, returning
+// false once s has ended
+func (s *Subscription) next() (ApplicantChange, bool) {
+	for {
+		s.feed.mu.Lock()
+		if len(s.order) > 0 {
+			id := s.order[0]
+			s.order = s.order[1:]
+			change := s.pending[id]
+			delete(s.pending, id)
+			s.feed.mu.Unlock()
+			return change, true
+		}
+		s.feed.mu.Unlock()
+
+		select {
+		case <-s.wake:
+		case <-s.ended:
+			return ApplicantChange{}, false
+		}
+	}
+}
+
+// Changes returns the channel changes are delivered on. It is closed when the
+// subscription ends.
+func (s *Subscription) Changes() <-chan ApplicantChange {
+	return s.changes
+}
+
+// Err returns why the subscription ended, once Changes is closed
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+// Close stops receiving changes
+func (s *Subscription) Close() {
+	s.feed.mu.Lock()
+	defer s.feed.mu.Unlock()
+	s.feed.end(s, nil)
+}
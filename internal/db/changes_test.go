@@ -0,0 +1,290 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+)
+
+// fakeApplicants counts the applicants loaded from it. Applicant 404 does not
+// exist and loading applicant 500 fails.
+type fakeApplicants struct {
+	mu    sync.Mutex
+	loads int
+}
+
+func (f *fakeApplicants) GetApplicant(ctx context.Context, id int64) (sqlc.Applicant, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.loads++
+
+	switch id {
+	case 404:
+		return sqlc.Applicant{}, sql.ErrNoRows
+	case 500:
+		return sqlc.Applicant{}, errors.New("connection refused")
+	}
+	return sqlc.Applicant{ID: id, Name: fmt.Sprintf("Applicant %d", id)}, nil
+}
+
+func (f *fakeApplicants) loaded() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.loads
+}
+
+// changeNotification announces a change of applicant id
+func changeNotification(changeType string, id int64, at time.Time) *pq.Notification {
+	return &pq.Notification{Extra: fmt.Sprintf(`{"type":%q,"id":%d,"at":%q}`, changeType, id, at.Format(time.RFC3339))}
+}
+
+// receive waits for the next change or the end of sub
+func receive(t *testing.T, sub *Subscription) (ApplicantChange, bool) {
+	t.Helper()
+	select {
+	case change, ok := <-sub.Changes():
+		return change, ok
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for a change")
+		return ApplicantChange{}, false
+	}
+}
+
+func TestChangeFeed(t *testing.T) {
+	t.Run("Changes reach every subscriber", func(t *testing.T) {
+		notifications := make(chan *pq.Notification)
+		feed := NewChangeFeed(notifications, &fakeApplicants{}, zap.NewNop())
+		defer close(notifications)
+
+		first, second := feed.Subscribe(), feed.Subscribe()
+		notifications <- &pq.Notification{Channel: ApplicantChangesChannel, Extra: `{"type":"UPDATED","id":7,"at":"2024-06-01T12:00:00Z"}`}
+
+		for _, sub := range []*Subscription{first, second} {
+			change, ok := receive(t, sub)
+			if !ok {
+				t.Fatalf("Expected a change, subscription ended with %v", sub.Err())
+			}
+			if change.Type != ChangeUpdated || change.ApplicantID != 7 || change.At.IsZero() {
+				t.Errorf("Unexpected change %+v", change)
+			}
+			if change.Applicant == nil || change.Applicant.ID != 7 {
+				t.Errorf("Expected the loaded applicant, got %+v", change.Applicant)
+			}
+		}
+	})
+
+	t.Run("Applicants are loaded once for every subscriber", func(t *testing.T) {
+		notifications := make(chan *pq.Notification)
+		applicants := &fakeApplicants{}
+		feed := NewChangeFeed(notifications, applicants, zap.NewNop())
+		defer close(notifications)
+
+		// Nobody listens, so nothing is loaded. The next notification is only
+		// taken once this one has been handled.
+		notifications <- changeNotification(ChangeCreated, 1, time.Now())
+		notifications <- &pq.Notification{Extra: "not json"}
+
+		subs := []*Subscription{feed.Subscribe(), feed.Subscribe(), feed.Subscribe()}
+		notifications <- changeNotification(ChangeDeleted, 2, time.Now())
+		// Deleted before it could be loaded
+		notifications <- changeNotification(ChangeUpdated, 404, time.Now())
+		notifications <- changeNotification(ChangeUpdated, 3, time.Now())
+
+		for _, sub := range subs {
+			if change, _ := receive(t, sub); change.ApplicantID != 2 || change.Applicant != nil {
+				t.Errorf("Expected the deletion of applicant 2, got %+v", change)
+			}
+			if change, _ := receive(t, sub); change.ApplicantID != 3 || change.Applicant == nil {
+				t.Errorf("Expected applicant 3 after skipping applicant 404, got %+v", change)
+			}
+		}
+		if n := applicants.loaded(); n != 2 {
+			t.Errorf("Expected applicants 404 and 3 to be loaded once each, got %d loads", n)
+		}
+	})
+
+	t.Run("A burst of changes is merged per applicant", func(t *testing.T) {
+		notifications := make(chan *pq.Notification)
+		applicants := &fakeApplicants{}
+		feed := NewChangeFeed(notifications, applicants, zap.NewNop())
+		defer close(notifications)
+
+		first, second := feed.Subscribe(), feed.Subscribe()
+
+		// Far more changes than a subscriber may fall behind by, to a few
+		// applicants, while neither subscriber receives
+		start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+		changes := 3 * subscriptionBuffer
+		var last time.Time
+		for i := 0; i < changes; i++ {
+			last = start.Add(time.Duration(i) * time.Second)
+			notifications <- changeNotification(ChangeUpdated, int64(i%3+1), last)
+		}
+
+		for _, sub := range []*Subscription{first, second} {
+			received := 0
+			for {
+				change, ok := receive(t, sub)
+				if !ok {
+					t.Fatalf("Expected the subscription to survive the burst, ended with %v", sub.Err())
+				}
+				received++
+				if change.At.Equal(last) {
+					break
+				}
+			}
+			// One change may already have been taken off the queue before the
+			// burst, plus one per applicant and the last change
+			if received > 5 {
+				t.Errorf("Expected the burst to be merged into a change per applicant, received %d", received)
+			}
+		}
+		if n := applicants.loaded(); n != changes {
+			t.Errorf("Expected each change to be loaded once for both subscribers, got %d loads for %d changes", n, changes)
+		}
+	})
+
+	t.Run("Failed load ends subscriptions", func(t *testing.T) {
+		notifications := make(chan *pq.Notification)
+		feed := NewChangeFeed(notifications, &fakeApplicants{}, zap.NewNop())
+		defer close(notifications)
+
+		sub := feed.Subscribe()
+		notifications <- changeNotification(ChangeUpdated, 500, time.Now())
+
+		if _, ok := receive(t, sub); ok {
+			t.Fatal("Expected the subscription to end")
+		}
+		if !errors.Is(sub.Err(), ErrChangeNotLoaded) {
+			t.Errorf("Expected ErrChangeNotLoaded, got %v", sub.Err())
+		}
+	})
+
+	t.Run("Invalid payloads are skipped", func(t *testing.T) {
+		notifications := make(chan *pq.Notification)
+		feed := NewChangeFeed(notifications, &fakeApplicants{}, zap.NewNop())
+		defer close(notifications)
+
+		sub := feed.Subscribe()
+		notifications <- &pq.Notification{Extra: "not json"}
+		notifications <- &pq.Notification{Extra: `{"type":"CREATED","id":1}`}
+
+		if change, _ := receive(t, sub); change.ApplicantID != 1 {
+			t.Errorf("Expected the valid change, got %+v", change)
+		}
+	})
+
+	t.Run("Reconnect ends subscriptions", func(t *testing.T) {
+		notifications := make(chan *pq.Notification)
+		feed := NewChangeFeed(notifications, &fakeApplicants{}, zap.NewNop())
+		defer close(notifications)
+
+		sub := feed.Subscribe()
+		notifications <- nil
+
+		if _, ok := receive(t, sub); ok {
+			t.Fatal("Expected the subscription to end")
+		}
+		if !errors.Is(sub.Err(), ErrChangesMissed) {
+			t.Errorf("Expected ErrChangesMissed, got %v", sub.Err())
+		}
+	})
+
+	t.Run("Slow subscriber is dropped", func(t *testing.T) {
+		notifications := make(chan *pq.Notification)
+		feed := NewChangeFeed(notifications, &fakeApplicants{}, zap.NewNop())
+		defer close(notifications)
+
+		slow := feed.Subscribe()
+		// Changes to more applicants than the subscriber may fall behind by,
+		// and one more in case the first was taken off the queue already
+		for i := 0; i < subscriptionBuffer+2; i++ {
+			notifications <- changeNotification(ChangeDeleted, int64(i+1), time.Now())
+		}
+		// Taken once the last change has been published
+		notifications <- &pq.Notification{Extra: "not json"}
+
+		// The change taken off the queue may still be delivered
+		received := 0
+		for {
+			if _, ok := receive(t, slow); !ok {
+				break
+			}
+			received++
+		}
+		if received > 1 {
+			t.Errorf("Expected undelivered changes to be dropped, received %d", received)
+		}
+		if !errors.Is(slow.Err(), ErrSubscriberTooSlow) {
+			t.Errorf("Expected ErrSubscriberTooSlow, got %v", slow.Err())
+		}
+	})
+
+	t.Run("Close ends subscriptions", func(t *testing.T) {
+		notifications := make(chan *pq.Notification)
+		feed := NewChangeFeed(notifications, &fakeApplicants{}, zap.NewNop())
+		defer close(notifications)
+
+		sub := feed.Subscribe()
+		if err := feed.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+
+		if _, ok := receive(t, sub); ok {
+			t.Fatal("Expected the subscription to end")
+		}
+		if !errors.Is(sub.Err(), ErrChangeFeedClosed) {
+			t.Errorf("Expected ErrChangeFeedClosed, got %v", sub.Err())
+		}
+		if late := feed.Subscribe(); !errors.Is(late.Err(), ErrChangeFeedClosed) {
+			t.Errorf("Expected subscriptions after Close to have ended, got %v", late.Err())
+		}
+	})
+
+	t.Run("Unsubscribed subscriber receives nothing", func(t *testing.T) {
+		notifications := make(chan *pq.Notification)
+		feed := NewChangeFeed(notifications, &fakeApplicants{}, zap.NewNop())
+		defer close(notifications)
+
+		sub := feed.Subscribe()
+		sub.Close()
+		notifications <- &pq.Notification{Extra: `{"type":"DELETED","id":3}`}
+
+		if _, ok := receive(t, sub); ok {
+			t.Fatal("Expected the subscription to have ended")
+		}
+		if sub.Err() != nil {
+			t.Errorf("Expected no error after Close, got %v", sub.Err())
+		}
+	})
+}
+
+func TestSubscription_queue(t *testing.T) {
+	sub := &Subscription{wake: make(chan struct{}, 1), pending: make(map[int64]ApplicantChange)}
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	sub.queue(ApplicantChange{Type: ChangeCreated, ApplicantID: 2, At: at, Applicant: &sqlc.Applicant{ID: 2, Name: "Before"}})
+	sub.queue(ApplicantChange{Type: ChangeUpdated, ApplicantID: 1, At: at.Add(time.Second), Applicant: &sqlc.Applicant{ID: 1}})
+	sub.queue(ApplicantChange{Type: ChangeUpdated, ApplicantID: 2, At: at.Add(2 * time.Second), Applicant: &sqlc.Applicant{ID: 2, Name: "After"}})
+	sub.queue(ApplicantChange{Type: ChangeDeleted, ApplicantID: 1, At: at.Add(3 * time.Second)})
+
+	if len(sub.order) != 2 || sub.order[0] != 2 || sub.order[1] != 1 {
+		t.Fatalf("Expected applicants 2 and 1 in the order they first changed, got %v", sub.order)
+	}
+	// The subscriber has not seen applicant 2 created yet
+	if created := sub.pending[2]; created.Type != ChangeCreated || created.Applicant.Name != "After" || !created.At.Equal(at.Add(2*time.Second)) {
+		t.Errorf("Expected the latest state of applicant 2 as its creation, got %+v", created)
+	}
+	if deleted := sub.pending[1]; deleted.Type != ChangeDeleted || deleted.Applicant != nil {
+		t.Errorf("Expected applicant 1 to be deleted, got %+v", deleted)
+	}
+}
@@ -0,0 +1,2 @@
+-- Create composite index backing keyset pagination in ListApplicantsByCursor
+CREATE INDEX idx_applicants_keyset ON applicants(overall_score DESC, created_at DESC, id DESC);
@@ -0,0 +1,10 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_applicants_search_trgm;
+DROP INDEX IF EXISTS idx_applicants_search_vector;
+
+-- Drop functions
+DROP FUNCTION IF EXISTS applicant_search_vector(TEXT, TEXT, TEXT, TEXT[], TEXT);
+DROP FUNCTION IF EXISTS applicant_search_text(TEXT, TEXT, TEXT, TEXT[], TEXT);
+
+-- Drop extension
+DROP EXTENSION IF EXISTS pg_trgm;
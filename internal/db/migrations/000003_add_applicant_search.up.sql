@@ -0,0 +1,38 @@
+-- Enable trigram matching for fuzzy search
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Create function building the lower-cased searchable text of an applicant
+-- (IMMUTABLE so it can back an expression index)
+CREATE OR REPLACE FUNCTION applicant_search_text(
+    p_name TEXT,
+    p_email TEXT,
+    p_position TEXT,
+    p_skills TEXT[],
+    p_fun_fact TEXT
+)
+RETURNS TEXT AS $$
+    SELECT lower(concat_ws(' ', p_name, p_email, p_position, array_to_string(p_skills, ' '), p_fun_fact));
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+-- Create function building the weighted full-text document of an applicant
+CREATE OR REPLACE FUNCTION applicant_search_vector(
+    p_name TEXT,
+    p_email TEXT,
+    p_position TEXT,
+    p_skills TEXT[],
+    p_fun_fact TEXT
+)
+RETURNS tsvector AS $$
+    SELECT
+        setweight(to_tsvector('simple', coalesce(p_name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(p_email, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(p_position, '')), 'B') ||
+        setweight(to_tsvector('simple', array_to_string(p_skills, ' ')), 'B') ||
+        setweight(to_tsvector('english', coalesce(p_fun_fact, '')), 'C');
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+-- Create indexes for full-text and trigram search
+CREATE INDEX idx_applicants_search_vector ON applicants
+    USING GIN (applicant_search_vector(name, email, position, skills, fun_fact));
+CREATE INDEX idx_applicants_search_trgm ON applicants
+    USING GIN (applicant_search_text(name, email, position, skills, fun_fact) gin_trgm_ops);
@@ -0,0 +1,8 @@
+-- Drop index
+DROP INDEX IF EXISTS idx_applicants_skills_normalized;
+
+-- Drop column
+ALTER TABLE applicants DROP COLUMN IF EXISTS skills_normalized;
+
+-- Drop function
+DROP FUNCTION IF EXISTS normalize_skills(TEXT[]);
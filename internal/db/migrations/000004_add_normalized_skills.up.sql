@@ -0,0 +1,12 @@
+-- Create function lower-casing skills so filters match like util.ContainsSkill
+CREATE OR REPLACE FUNCTION normalize_skills(p_skills TEXT[])
+RETURNS TEXT[] AS $$
+    SELECT ARRAY(SELECT lower(btrim(s)) FROM unnest(p_skills) AS s);
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+-- Add normalized skills column maintained by Postgres
+ALTER TABLE applicants
+    ADD COLUMN skills_normalized TEXT[] GENERATED ALWAYS AS (normalize_skills(skills)) STORED;
+
+-- Create index for skill containment/overlap filters
+CREATE INDEX idx_applicants_skills_normalized ON applicants USING GIN (skills_normalized);
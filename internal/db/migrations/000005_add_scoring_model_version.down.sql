@@ -0,0 +1,2 @@
+-- Drop scoring model version column
+ALTER TABLE applicants DROP COLUMN IF EXISTS scoring_model_version;
@@ -0,0 +1,3 @@
+-- Record which scoring model produced each applicant's overall score
+ALTER TABLE applicants
+    ADD COLUMN scoring_model_version VARCHAR(64) NOT NULL DEFAULT 'builtin-v1';
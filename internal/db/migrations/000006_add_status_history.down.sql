@@ -0,0 +1,2 @@
+-- Drop status history table
+DROP TABLE IF EXISTS applicant_status_history;
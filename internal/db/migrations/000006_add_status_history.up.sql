@@ -0,0 +1,13 @@
+-- Record every status change of an applicant: who made it, when and why
+CREATE TABLE IF NOT EXISTS applicant_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    applicant_id BIGINT NOT NULL REFERENCES applicants(id) ON DELETE CASCADE,
+    from_status INTEGER NOT NULL,
+    to_status INTEGER NOT NULL,
+    changed_by VARCHAR(255) NOT NULL DEFAULT '',
+    reason TEXT,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_applicant_status_history_applicant
+    ON applicant_status_history(applicant_id, changed_at, id);
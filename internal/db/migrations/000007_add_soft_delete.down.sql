@@ -0,0 +1,8 @@
+-- Soft-deleted applicants cannot be represented without deleted_at, so purge them
+DELETE FROM applicants WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_applicants_email_active;
+ALTER TABLE applicants ADD CONSTRAINT applicants_email_key UNIQUE (email);
+
+DROP INDEX IF EXISTS idx_applicants_deleted_at;
+ALTER TABLE applicants DROP COLUMN IF EXISTS deleted_at;
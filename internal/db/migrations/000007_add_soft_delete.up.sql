@@ -0,0 +1,8 @@
+-- Soft delete: deleted applicants keep their row until purged
+ALTER TABLE applicants ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX idx_applicants_deleted_at ON applicants(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Only active applicants need unique emails, so a deleted applicant can re-apply
+ALTER TABLE applicants DROP CONSTRAINT IF EXISTS applicants_email_key;
+CREATE UNIQUE INDEX idx_applicants_email_active ON applicants(email) WHERE deleted_at IS NULL;
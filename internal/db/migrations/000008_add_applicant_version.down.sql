@@ -0,0 +1,7 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS increment_applicants_version ON applicants;
+
+-- Drop function
+DROP FUNCTION IF EXISTS increment_version_column();
+
+ALTER TABLE applicants DROP COLUMN IF EXISTS version;
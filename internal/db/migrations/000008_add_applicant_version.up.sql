@@ -0,0 +1,17 @@
+-- Optimistic concurrency: every update bumps the version, which clients see as the applicant's etag
+ALTER TABLE applicants ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+-- Create function to increment the row version
+CREATE OR REPLACE FUNCTION increment_version_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+-- Create trigger to automatically increment version
+CREATE TRIGGER increment_applicants_version
+    BEFORE UPDATE ON applicants
+    FOR EACH ROW
+    EXECUTE FUNCTION increment_version_column();
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_applicants_agency;
+
+ALTER TABLE applicants DROP COLUMN IF EXISTS agency;
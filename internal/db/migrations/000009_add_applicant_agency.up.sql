@@ -0,0 +1,4 @@
+-- Recruiting agency that submitted the applicant; NULL for direct applications
+ALTER TABLE applicants ADD COLUMN agency TEXT;
+
+CREATE INDEX idx_applicants_agency ON applicants(agency) WHERE agency IS NOT NULL;
@@ -0,0 +1,7 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS applicant_audit_log_append_only ON applicant_audit_log;
+
+-- Drop function
+DROP FUNCTION IF EXISTS prevent_audit_log_changes();
+
+DROP TABLE IF EXISTS applicant_audit_log;
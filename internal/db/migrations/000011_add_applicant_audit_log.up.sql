@@ -0,0 +1,29 @@
+-- Append-only record of every mutation to an applicant: who made it, through
+-- which request and method, and which fields changed. There is no foreign key
+-- so the trail survives purges.
+CREATE TABLE IF NOT EXISTS applicant_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    applicant_id BIGINT NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    method VARCHAR(255) NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_applicant_audit_log_applicant ON applicant_audit_log(applicant_id, id);
+CREATE INDEX idx_applicant_audit_log_actor ON applicant_audit_log(actor, id);
+CREATE INDEX idx_applicant_audit_log_created_at ON applicant_audit_log(created_at);
+
+-- Reject any attempt to rewrite history
+CREATE OR REPLACE FUNCTION prevent_audit_log_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'applicant_audit_log is append-only';
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER applicant_audit_log_append_only
+    BEFORE UPDATE OR DELETE ON applicant_audit_log
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_audit_log_changes();
@@ -0,0 +1,5 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS applicants_notify_change ON applicants;
+
+-- Drop function
+DROP FUNCTION IF EXISTS notify_applicant_change();
@@ -0,0 +1,47 @@
+-- Announce every change to an applicant on the applicant_changes channel for
+-- WatchApplicants. Notifications are delivered when the transaction commits.
+-- The payload only carries the kind of change and the applicant ID, far below
+-- the 8000 byte NOTIFY limit; listeners load the applicant themselves.
+CREATE OR REPLACE FUNCTION notify_applicant_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    change TEXT;
+    applicant_id BIGINT;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        change := 'CREATED';
+        applicant_id := NEW.id;
+    ELSIF TG_OP = 'DELETE' THEN
+        -- Purged applicants were announced as deleted when soft-deleted
+        IF OLD.deleted_at IS NOT NULL THEN
+            RETURN NULL;
+        END IF;
+        change := 'DELETED';
+        applicant_id := OLD.id;
+    ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+        change := 'DELETED';
+        applicant_id := NEW.id;
+    ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+        change := 'CREATED';
+        applicant_id := NEW.id;
+    ELSIF NEW.deleted_at IS NOT NULL THEN
+        -- Changes to soft-deleted applicants are not visible
+        RETURN NULL;
+    ELSE
+        change := 'UPDATED';
+        applicant_id := NEW.id;
+    END IF;
+
+    PERFORM pg_notify('applicant_changes', json_build_object(
+        'type', change,
+        'id', applicant_id,
+        'at', NOW()
+    )::text);
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER applicants_notify_change
+    AFTER INSERT OR UPDATE OR DELETE ON applicants
+    FOR EACH ROW
+    EXECUTE FUNCTION notify_applicant_change();
@@ -0,0 +1,3 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_applicants_search_trgm_no_email;
+DROP INDEX IF EXISTS idx_applicants_search_vector_no_email;
@@ -0,0 +1,6 @@
+-- Index the search document without email, which SearchApplicants matches
+-- for callers that may not see the emails of the applicants they find
+CREATE INDEX idx_applicants_search_vector_no_email ON applicants
+    USING GIN (applicant_search_vector(name, NULL, position, skills, fun_fact));
+CREATE INDEX idx_applicants_search_trgm_no_email ON applicants
+    USING GIN (applicant_search_text(name, NULL, position, skills, fun_fact) gin_trgm_ops);
@@ -0,0 +1,39 @@
+-- name: CreateApiKey :one
+-- Store a new API key by its hash
+INSERT INTO api_keys (
+    name,
+    owner,
+    key_prefix,
+    key_hash,
+    scopes,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetActiveApiKeyByHash :one
+-- Get an API key that is neither revoked nor expired
+SELECT * FROM api_keys
+WHERE key_hash = $1
+    AND revoked_at IS NULL
+    AND (expires_at IS NULL OR expires_at > NOW());
+
+-- name: ListApiKeys :many
+-- List all API keys, newest first
+SELECT * FROM api_keys
+ORDER BY created_at DESC, id DESC;
+
+-- name: RevokeApiKey :one
+-- Revoke an API key so it can no longer be used
+UPDATE api_keys
+SET revoked_at = NOW()
+WHERE id = $1 AND revoked_at IS NULL
+RETURNING *;
+
+-- name: TouchApiKey :exec
+-- Record that an API key was used, at most once a minute
+UPDATE api_keys
+SET last_used_at = NOW()
+WHERE id = $1
+    AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute');
@@ -1,30 +1,60 @@
 -- name: GetApplicant :one
 -- Get a single applicant by ID
 SELECT * FROM applicants
-WHERE id = $1 LIMIT 1;
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1;
+
+-- name: GetApplicantForUpdate :one
+-- Get a single applicant by ID and lock the row for the rest of the transaction
+SELECT * FROM applicants
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1
+FOR UPDATE;
 
 -- name: GetApplicantByEmail :one
 -- Get a single applicant by email address
 SELECT * FROM applicants
-WHERE email = $1 LIMIT 1;
+WHERE email = $1 AND deleted_at IS NULL LIMIT 1;
 
 -- name: ListApplicants :many
 -- List applicants with pagination and optional filtering
 SELECT * FROM applicants
 WHERE
-    (sqlc.arg(position)::text = '' OR position = sqlc.arg(position)::text)
+    deleted_at IS NULL
+    AND (sqlc.arg(position)::text = '' OR position = sqlc.arg(position)::text)
     AND (sqlc.arg(status)::integer <= 0 OR status = sqlc.arg(status)::integer)
     AND (sqlc.arg(min_score)::double precision <= 0 OR overall_score >= sqlc.arg(min_score)::double precision)
-ORDER BY overall_score DESC, created_at DESC
+    AND (COALESCE(cardinality(sqlc.arg(skills_any)::text[]), 0) = 0 OR skills_normalized && sqlc.arg(skills_any)::text[])
+    AND (COALESCE(cardinality(sqlc.arg(skills_all)::text[]), 0) = 0 OR skills_normalized @> sqlc.arg(skills_all)::text[])
+ORDER BY overall_score DESC, created_at DESC, id DESC
 LIMIT $1 OFFSET $2;
 
+-- name: ListApplicantsByCursor :many
+-- List applicants after a (overall_score, created_at, id) keyset cursor with optional filtering
+SELECT * FROM applicants
+WHERE
+    deleted_at IS NULL
+    AND (sqlc.arg(position)::text = '' OR position = sqlc.arg(position)::text)
+    AND (sqlc.arg(status)::integer <= 0 OR status = sqlc.arg(status)::integer)
+    AND (sqlc.arg(min_score)::double precision <= 0 OR overall_score >= sqlc.arg(min_score)::double precision)
+    AND (COALESCE(cardinality(sqlc.arg(skills_any)::text[]), 0) = 0 OR skills_normalized && sqlc.arg(skills_any)::text[])
+    AND (COALESCE(cardinality(sqlc.arg(skills_all)::text[]), 0) = 0 OR skills_normalized @> sqlc.arg(skills_all)::text[])
+    AND (overall_score, created_at, id) < (
+        sqlc.arg(cursor_score)::double precision,
+        sqlc.arg(cursor_created_at)::timestamptz,
+        sqlc.arg(cursor_id)::bigint
+    )
+ORDER BY overall_score DESC, created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
 -- name: CountApplicants :one
 -- Count total applicants with optional filtering
 SELECT COUNT(*) FROM applicants
 WHERE
-    (sqlc.arg(position)::text = '' OR position = sqlc.arg(position)::text)
+    deleted_at IS NULL
+    AND (sqlc.arg(position)::text = '' OR position = sqlc.arg(position)::text)
     AND (sqlc.arg(status)::integer <= 0 OR status = sqlc.arg(status)::integer)
-    AND (sqlc.arg(min_score)::double precision <= 0 OR overall_score >= sqlc.arg(min_score)::double precision);
+    AND (sqlc.arg(min_score)::double precision <= 0 OR overall_score >= sqlc.arg(min_score)::double precision)
+    AND (COALESCE(cardinality(sqlc.arg(skills_any)::text[]), 0) = 0 OR skills_normalized && sqlc.arg(skills_any)::text[])
+    AND (COALESCE(cardinality(sqlc.arg(skills_all)::text[]), 0) = 0 OR skills_normalized @> sqlc.arg(skills_all)::text[]);
 
 -- name: CreateApplicant :one
 -- Create a new applicant
@@ -45,9 +75,11 @@ INSERT INTO applicants (
     status,
     fun_fact,
     availability,
-    salary_expectation
+    salary_expectation,
+    scoring_model_version,
+    agency
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
 ) RETURNING *;
 
 -- name: UpdateApplicant :one
@@ -70,37 +102,78 @@ SET
     status = $15,
     fun_fact = $16,
     availability = $17,
-    salary_expectation = $18
-WHERE id = $1
+    salary_expectation = $18,
+    scoring_model_version = $19
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteApplicant :execrows
+-- Soft-delete an applicant by ID; the row is kept until it is purged
+UPDATE applicants
+SET deleted_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreApplicant :one
+-- Restore a soft-deleted applicant
+UPDATE applicants
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
 RETURNING *;
 
--- name: DeleteApplicant :exec
--- Delete an applicant by ID
+-- name: PurgeApplicant :execrows
+-- Permanently delete a soft-deleted applicant
 DELETE FROM applicants
-WHERE id = $1;
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: CountPurgeableApplicants :one
+-- Count applicants soft-deleted before the given time
+SELECT COUNT(*) FROM applicants
+WHERE deleted_at < sqlc.arg(deleted_before)::timestamptz;
+
+-- name: PurgeDeletedApplicants :execrows
+-- Permanently delete applicants soft-deleted before the given time, recording
+-- an audit event without any of their data for each one in the same statement
+WITH purged AS (
+    DELETE FROM applicants
+    WHERE deleted_at < sqlc.arg(deleted_before)::timestamptz
+    RETURNING id
+)
+INSERT INTO applicant_audit_log (applicant_id, actor, method)
+SELECT id, sqlc.arg(actor)::text, sqlc.arg(method)::text
+FROM purged;
 
 -- name: GetTopApplicantsByPosition :many
 -- Get top N applicants for a specific position, ordered by overall score
 SELECT * FROM applicants
-WHERE position = $1
+WHERE position = $1 AND deleted_at IS NULL
 ORDER BY overall_score DESC
 LIMIT $2;
 
 -- name: GetBestApplicant :one
 -- Get the best applicant (Jonathan Søholm-Boesen should always be returned)
 SELECT * FROM applicants
+WHERE deleted_at IS NULL
 ORDER BY overall_score DESC, created_at ASC
 LIMIT 1;
 
 -- name: UpdateApplicantScore :one
--- Update only the overall score of an applicant
+-- Update only the overall score of an applicant and the model that produced it
 UPDATE applicants
-SET overall_score = $2
+SET
+    overall_score = $2,
+    scoring_model_version = $3
 WHERE id = $1
 RETURNING *;
 
+-- name: UpdateApplicantStatus :one
+-- Update only the status of an applicant
+UPDATE applicants
+SET status = $2
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
 -- name: DeleteAllApplicants :exec
--- Delete all applicants (used for seeding/testing)
+-- Permanently delete all applicants, including soft-deleted ones (used for seeding/testing)
 DELETE FROM applicants;
 
 -- name: GetApplicantStats :one
@@ -111,4 +184,81 @@ SELECT
     MAX(overall_score) as max_score,
     MIN(overall_score) as min_score,
     AVG(years_experience) as avg_experience
-FROM applicants;
+FROM applicants
+WHERE deleted_at IS NULL;
+
+-- name: CountApplicantsByStatus :many
+-- Count active applicants and their average overall score per status, for metrics
+SELECT
+    status,
+    COUNT(*) AS applicants,
+    COALESCE(AVG(overall_score), 0)::float8 AS avg_score
+FROM applicants
+WHERE deleted_at IS NULL
+GROUP BY status;
+
+-- name: SearchApplicants :many
+-- Full-text and fuzzy search over name, email, position, skills and fun_fact, ranked by relevance.
+-- Email is only matched when search_email is set or the applicant belongs to email_agency,
+-- so callers cannot probe for addresses that are redacted from their results.
+SELECT
+    sqlc.embed(applicants),
+    (CASE
+        WHEN sqlc.arg(search_email)::boolean OR agency = NULLIF(sqlc.arg(email_agency)::text, '') THEN
+            ts_rank(
+                applicant_search_vector(name, email, position, skills, fun_fact),
+                websearch_to_tsquery('simple', sqlc.arg(query)::text) || websearch_to_tsquery('english', sqlc.arg(query)::text)
+            )
+            + word_similarity(lower(sqlc.arg(query)::text), applicant_search_text(name, email, position, skills, fun_fact))
+        ELSE
+            ts_rank(
+                applicant_search_vector(name, NULL, position, skills, fun_fact),
+                websearch_to_tsquery('simple', sqlc.arg(query)::text) || websearch_to_tsquery('english', sqlc.arg(query)::text)
+            )
+            + word_similarity(lower(sqlc.arg(query)::text), applicant_search_text(name, NULL, position, skills, fun_fact))
+    END)::double precision AS rank,
+    -- The text is HTML-escaped before highlighting so stored markup can never
+    -- reach clients rendering the snippet as HTML. Email is left out since it is
+    -- redacted for some callers; keep these fields in sync with redact.snippetFields.
+    ts_headline(
+        'english',
+        replace(replace(replace(replace(
+            concat_ws(' | ', name, position, array_to_string(skills, ', '), fun_fact),
+            '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'),
+        websearch_to_tsquery('simple', sqlc.arg(query)::text) || websearch_to_tsquery('english', sqlc.arg(query)::text),
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5'
+    )::text AS snippet
+FROM applicants
+WHERE
+    deleted_at IS NULL
+    AND (
+        applicant_search_vector(name, NULL, position, skills, fun_fact)
+            @@ (websearch_to_tsquery('simple', sqlc.arg(query)::text) || websearch_to_tsquery('english', sqlc.arg(query)::text))
+        OR lower(sqlc.arg(query)::text) <% applicant_search_text(name, NULL, position, skills, fun_fact)
+        OR (
+            (sqlc.arg(search_email)::boolean OR agency = NULLIF(sqlc.arg(email_agency)::text, ''))
+            AND (
+                applicant_search_vector(name, email, position, skills, fun_fact)
+                    @@ (websearch_to_tsquery('simple', sqlc.arg(query)::text) || websearch_to_tsquery('english', sqlc.arg(query)::text))
+                OR lower(sqlc.arg(query)::text) <% applicant_search_text(name, email, position, skills, fun_fact)
+            )
+        )
+    )
+ORDER BY rank DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: ListApplicantsForRescore :many
+-- List applicants in ID order after a checkpoint, used to recompute scores in batches.
+-- Soft-deleted applicants are included so they have current scores if restored.
+SELECT * FROM applicants
+WHERE id > sqlc.arg(after_id)::bigint
+ORDER BY id ASC
+LIMIT sqlc.arg(batch_size)
+FOR UPDATE;
+
+-- name: PreviewApplicantsForRescore :many
+-- Same as ListApplicantsForRescore without locking the rows, for dry runs that only report changes
+SELECT * FROM applicants
+WHERE id > sqlc.arg(after_id)::bigint
+ORDER BY id ASC
+LIMIT sqlc.arg(batch_size);
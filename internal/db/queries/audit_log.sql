@@ -0,0 +1,24 @@
+-- name: CreateAuditEvent :one
+-- Append a mutation to the audit log
+INSERT INTO applicant_audit_log (
+    applicant_id,
+    actor,
+    request_id,
+    method,
+    changes
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: ListAuditEvents :many
+-- List audit events, newest first, optionally filtered by applicant, actor and
+-- time range [start_time, end_time); before_id continues after a previous page
+SELECT * FROM applicant_audit_log
+WHERE (sqlc.arg(applicant_id)::bigint = 0 OR applicant_id = sqlc.arg(applicant_id)::bigint)
+    AND (sqlc.arg(actor)::text = '' OR actor = sqlc.arg(actor)::text)
+    AND (sqlc.narg(start_time)::timestamptz IS NULL OR created_at >= sqlc.narg(start_time)::timestamptz)
+    AND (sqlc.narg(end_time)::timestamptz IS NULL OR created_at < sqlc.narg(end_time)::timestamptz)
+    AND (sqlc.arg(before_id)::bigint = 0 OR id < sqlc.arg(before_id)::bigint)
+ORDER BY id DESC
+LIMIT sqlc.arg(page_size);
@@ -0,0 +1,18 @@
+-- name: CreateStatusHistoryEntry :one
+-- Record a status change of an applicant
+INSERT INTO applicant_status_history (
+    applicant_id,
+    from_status,
+    to_status,
+    changed_by,
+    reason
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: ListStatusHistory :many
+-- List the status changes of an applicant, oldest first
+SELECT * FROM applicant_status_history
+WHERE applicant_id = $1
+ORDER BY changed_at ASC, id ASC;
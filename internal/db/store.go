@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+)
+
+// Store provides all queries plus the ability to run several of them in one transaction
+type Store interface {
+	sqlc.Querier
+	ExecTx(ctx context.Context, fn func(q sqlc.Querier) error) error
+}
+
+// SQLStore implements Store on top of a *sql.DB
+type SQLStore struct {
+	*sqlc.Queries
+	db *sql.DB
+}
+
+// NewStore creates a new SQL-backed store. Every query is traced with the
+// global tracer provider.
+func NewStore(db *sql.DB) *SQLStore {
+	return &SQLStore{
+		Queries: sqlc.New(tracedDBTX{db: db}),
+		db:      db,
+	}
+}
+
+// ExecTx runs fn within a database transaction, committing if fn succeeds and rolling back otherwise
+func (s *SQLStore) ExecTx(ctx context.Context, fn func(q sqlc.Querier) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(sqlc.New(tracedDBTX{db: tx})); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
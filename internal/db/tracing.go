@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+)
+
+// tracer creates the spans of database queries
+var tracer = otel.Tracer("github.com/Thrun12/golang-assignment/internal/db")
+
+// tracedDBTX wraps the connection or transaction used by sqlc.Queries with a
+// span per query, named after the sqlc query
+type tracedDBTX struct {
+	db sqlc.DBTX
+}
+
+func (t tracedDBTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	result, err := t.db.ExecContext(ctx, query, args...)
+	endQuerySpan(span, err)
+	return result, err
+}
+
+func (t tracedDBTX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	stmt, err := t.db.PrepareContext(ctx, query)
+	endQuerySpan(span, err)
+	return stmt, err
+}
+
+func (t tracedDBTX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	rows, err := t.db.QueryContext(ctx, query, args...)
+	endQuerySpan(span, err)
+	return rows, err
+}
+
+func (t tracedDBTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := startQuerySpan(ctx, query)
+	row := t.db.QueryRowContext(ctx, query, args...)
+	endQuerySpan(span, row.Err())
+	return row
+}
+
+// startQuerySpan starts a client span for query. Query arguments are never
+// recorded since they hold applicant data.
+func startQuerySpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	name := queryName(query)
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationName(name),
+			semconv.DBQueryText(query),
+		),
+	)
+}
+
+// endQuerySpan ends span, marking it failed if err is set
+func endQuerySpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// queryName returns the name of a sqlc query from the "-- name: GetApplicant :one"
+// comment sqlc puts in front of every query
+func queryName(query string) string {
+	rest, ok := strings.CutPrefix(query, "-- name: ")
+	if !ok {
+		return "query"
+	}
+	if i := strings.IndexAny(rest, " \n"); i > 0 {
+		return rest[:i]
+	}
+	return rest
+}
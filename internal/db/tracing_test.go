@@ -0,0 +1,23 @@
+package db
+
+import "testing"
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "sqlc query", query: "-- name: GetApplicant :one\nSELECT id FROM applicants WHERE id = $1", want: "GetApplicant"},
+		{name: "Name only", query: "-- name: ListApplicants", want: "ListApplicants"},
+		{name: "Plain SQL", query: "SELECT 1", want: "query"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queryName(tt.query); got != tt.want {
+				t.Errorf("queryName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
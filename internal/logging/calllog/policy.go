@@ -0,0 +1,161 @@
+package calllog
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"go.yaml.in/yaml/v3"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+)
+
+// Method describes how calls to a method are logged. Unset fields of a
+// method entry inherit the default.
+type Method struct {
+	// Level is the level successful calls are logged at
+	Level string `yaml:"level"`
+
+	// Payloads logs request and response messages, with sensitive fields redacted
+	Payloads *bool `yaml:"payloads"`
+
+	// SlowThreshold is the duration above which calls are logged as slow at
+	// Warn level or above
+	SlowThreshold time.Duration `yaml:"slow_threshold"`
+}
+
+// File is the layout of a log policy file (YAML or JSON)
+type File struct {
+	// Default applies to every method without an entry of its own
+	Default Method `yaml:"default"`
+
+	// Methods overrides the default for full gRPC method names
+	Methods map[string]Method `yaml:"methods"`
+}
+
+// Rule is the resolved log policy of a method
+type Rule struct {
+	Level         zapcore.Level
+	Payloads      bool
+	SlowThreshold time.Duration
+}
+
+// Policy decides how calls are logged
+type Policy struct {
+	defaultRule Rule
+	methods     map[string]Rule
+}
+
+// DefaultPolicy returns the built-in policy: calls are logged at Info without
+// payloads and reported as slow above one second. Lookups that clients poll,
+// such as the best applicant and the scoring models, are logged at Debug, and
+// score recomputation, which walks every applicant, is slow above 30 seconds.
+func DefaultPolicy() *Policy {
+	debug := Method{Level: "debug"}
+	p, err := NewPolicy(File{
+		Default: Method{Level: "info", Payloads: new(bool), SlowThreshold: time.Second},
+		Methods: map[string]Method{
+			applicantsv1.ApplicantsService_GetBestApplicant_FullMethodName:  debug,
+			applicantsv1.ApplicantsService_ListScoringModels_FullMethodName: debug,
+			applicantsv1.ApplicantsService_GetScoringModel_FullMethodName:   debug,
+			applicantsv1.ApplicantsService_RecomputeScores_FullMethodName:   {SlowThreshold: 30 * time.Second},
+		},
+	})
+	if err != nil {
+		panic(fmt.Sprintf("invalid default log policy: %v", err))
+	}
+	return p
+}
+
+// NewPolicy creates a policy from a parsed policy file
+func NewPolicy(file File) (*Policy, error) {
+	if file.Default.Level == "" {
+		return nil, fmt.Errorf("default: level is required")
+	}
+	defaultRule, err := file.Default.resolve(Rule{})
+	if err != nil {
+		return nil, fmt.Errorf("default: %w", err)
+	}
+
+	known := make(map[string]bool)
+	for _, m := range applicantsv1.ApplicantsService_ServiceDesc.Methods {
+		known["/"+applicantsv1.ApplicantsService_ServiceDesc.ServiceName+"/"+m.MethodName] = true
+	}
+	for _, st := range applicantsv1.ApplicantsService_ServiceDesc.Streams {
+		known["/"+applicantsv1.ApplicantsService_ServiceDesc.ServiceName+"/"+st.StreamName] = true
+	}
+
+	p := &Policy{
+		defaultRule: defaultRule,
+		methods:     make(map[string]Rule, len(file.Methods)),
+	}
+	for method, m := range file.Methods {
+		if !known[method] {
+			return nil, fmt.Errorf("method %q: unknown method", method)
+		}
+		rule, err := m.resolve(defaultRule)
+		if err != nil {
+			return nil, fmt.Errorf("method %q: %w", method, err)
+		}
+		p.methods[method] = rule
+	}
+	return p, nil
+}
+
+// resolve fills in the fields of m over base
+func (m Method) resolve(base Rule) (Rule, error) {
+	rule := base
+	if m.Level != "" {
+		level, err := zapcore.ParseLevel(m.Level)
+		if err != nil {
+			return Rule{}, err
+		}
+		rule.Level = level
+	}
+	if m.Payloads != nil {
+		rule.Payloads = *m.Payloads
+	}
+	if m.SlowThreshold < 0 {
+		return Rule{}, fmt.Errorf("slow_threshold must not be negative")
+	}
+	if m.SlowThreshold > 0 {
+		rule.SlowThreshold = m.SlowThreshold
+	}
+	return rule, nil
+}
+
+// LoadPolicy loads a log policy from a YAML or JSON file.
+// An empty path yields the default policy.
+func LoadPolicy(path string) (*Policy, error) {
+	if path == "" {
+		return DefaultPolicy(), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log policy: %w", err)
+	}
+	defer f.Close()
+
+	var file File
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse log policy %s: %w", path, err)
+	}
+
+	p, err := NewPolicy(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log policy %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Rule returns how calls to method are logged
+func (p *Policy) Rule(method string) Rule {
+	if rule, ok := p.methods[method]; ok {
+		return rule
+	}
+	return p.defaultRule
+}
@@ -0,0 +1,87 @@
+package calllog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+)
+
+func writePolicy(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write policy file: %v", err)
+	}
+	return path
+}
+
+func TestLoadPolicy(t *testing.T) {
+	t.Run("Shipped policy file", func(t *testing.T) {
+		p, err := LoadPolicy("../../../configs/log_policy.yaml")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if rule := p.Rule(applicantsv1.ApplicantsService_GetApplicant_FullMethodName); rule != DefaultPolicy().defaultRule {
+			t.Errorf("Expected GetApplicant to use the default rule, got %+v", rule)
+		}
+
+		best := p.Rule(applicantsv1.ApplicantsService_GetBestApplicant_FullMethodName)
+		if best.Level != zapcore.DebugLevel || best.SlowThreshold != time.Second {
+			t.Errorf("Expected GetBestApplicant at debug with the default threshold, got %+v", best)
+		}
+
+		recompute := p.Rule(applicantsv1.ApplicantsService_RecomputeScores_FullMethodName)
+		if recompute.Level != zapcore.InfoLevel || recompute.SlowThreshold != 30*time.Second {
+			t.Errorf("Expected RecomputeScores at info with a 30s threshold, got %+v", recompute)
+		}
+	})
+
+	t.Run("Method inherits unset fields", func(t *testing.T) {
+		p, err := LoadPolicy(writePolicy(t, `
+default: {level: warn, payloads: true, slow_threshold: 2s}
+methods:
+  /applicants.v1.ApplicantsService/CreateApplicant: {payloads: false}
+`))
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		want := Rule{Level: zapcore.WarnLevel, SlowThreshold: 2 * time.Second}
+		if rule := p.Rule(applicantsv1.ApplicantsService_CreateApplicant_FullMethodName); rule != want {
+			t.Errorf("Expected %+v, got %+v", want, rule)
+		}
+	})
+
+	t.Run("Unknown method", func(t *testing.T) {
+		_, err := LoadPolicy(writePolicy(t, `
+default: {level: info}
+methods:
+  /applicants.v1.ApplicantsService/DeleteAllApplicants: {level: debug}
+`))
+		if err == nil || !strings.Contains(err.Error(), "unknown method") {
+			t.Errorf("Expected unknown method error, got: %v", err)
+		}
+	})
+
+	t.Run("Invalid level", func(t *testing.T) {
+		if _, err := LoadPolicy(writePolicy(t, "default: {level: verbose}\n")); err == nil {
+			t.Error("Expected error for invalid level")
+		}
+		if _, err := LoadPolicy(writePolicy(t, "default: {payloads: true}\n")); err == nil {
+			t.Error("Expected error for missing default level")
+		}
+	})
+
+	t.Run("Unknown key", func(t *testing.T) {
+		if _, err := LoadPolicy(writePolicy(t, "default: {level: info, payload: true}\n")); err == nil {
+			t.Error("Expected error for unknown key")
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package calllog
+
+import (
+	"encoding/json"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/logging"
+)
+
+// Redact returns a copy of msg with every field marked (applicants.v1.sensitive)
+// redacted, at any depth. Sensitive strings read logging.Redacted; other sensitive
+// fields are cleared.
+func Redact(msg proto.Message) proto.Message {
+	clone := proto.Clone(msg)
+	redact(clone.ProtoReflect())
+	return clone
+}
+
+func redact(m protoreflect.Message) {
+	var sensitive []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case isSensitive(fd):
+			sensitive = append(sensitive, fd)
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, value protoreflect.Value) bool {
+					redact(value.Message())
+					return true
+				})
+			}
+		case fd.IsList():
+			if fd.Message() != nil {
+				list := v.List()
+				for i := 0; i < list.Len(); i++ {
+					redact(list.Get(i).Message())
+				}
+			}
+		case fd.Message() != nil:
+			redact(v.Message())
+		}
+		return true
+	})
+
+	for _, fd := range sensitive {
+		if fd.Kind() == protoreflect.StringKind && fd.Cardinality() != protoreflect.Repeated {
+			m.Set(fd, protoreflect.ValueOfString(logging.Redacted))
+		} else {
+			m.Clear(fd)
+		}
+	}
+}
+
+// isSensitive reports whether fd is marked (applicants.v1.sensitive)
+func isSensitive(fd protoreflect.FieldDescriptor) bool {
+	sensitive, _ := proto.GetExtension(fd.Options(), applicantsv1.E_Sensitive).(bool)
+	return sensitive
+}
+
+// Payload returns a log field holding msg as JSON with sensitive fields
+// redacted. Values that are not protobuf messages are skipped.
+func Payload(key string, msg interface{}) zap.Field {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return zap.Skip()
+	}
+	data, err := protojson.Marshal(Redact(m))
+	if err != nil {
+		return zap.String(key, "<"+err.Error()+">")
+	}
+	return zap.Reflect(key, json.RawMessage(data))
+}
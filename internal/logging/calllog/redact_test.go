@@ -0,0 +1,98 @@
+package calllog
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/logging"
+)
+
+func TestRedact(t *testing.T) {
+	t.Run("Top-level fields", func(t *testing.T) {
+		req := &applicantsv1.CreateApplicantRequest{
+			Name:              "Ada Lovelace",
+			Email:             "ada@example.com",
+			SalaryExpectation: "150k",
+		}
+
+		got := Redact(req).(*applicantsv1.CreateApplicantRequest)
+		if got.Email != logging.Redacted || got.SalaryExpectation != logging.Redacted {
+			t.Errorf("Expected email and salary expectation to be redacted, got %q and %q", got.Email, got.SalaryExpectation)
+		}
+		if got.Name != "Ada Lovelace" {
+			t.Errorf("Expected name to be kept, got %q", got.Name)
+		}
+		if req.Email != "ada@example.com" {
+			t.Error("Expected the original message to be left untouched")
+		}
+	})
+
+	t.Run("Nested and repeated messages", func(t *testing.T) {
+		resp := &applicantsv1.ListApplicantsResponse{
+			Applicants: []*applicantsv1.JobApplicant{
+				{Id: 1, Email: "a@example.com"},
+				{Id: 2, Email: "b@example.com"},
+			},
+		}
+
+		got := Redact(resp).(*applicantsv1.ListApplicantsResponse)
+		for _, a := range got.Applicants {
+			if a.Email != logging.Redacted {
+				t.Errorf("Expected email of applicant %d to be redacted, got %q", a.Id, a.Email)
+			}
+		}
+	})
+
+	t.Run("Issued API key", func(t *testing.T) {
+		resp := &applicantsv1.CreateApiKeyResponse{
+			ApiKey: &applicantsv1.ApiKey{Id: 7, Name: "ci"},
+			Key:    "ak_secret",
+		}
+
+		got := Redact(resp).(*applicantsv1.CreateApiKeyResponse)
+		if got.Key != logging.Redacted {
+			t.Errorf("Expected key to be redacted, got %q", got.Key)
+		}
+		if got.ApiKey.GetName() != "ci" {
+			t.Errorf("Expected key metadata to be kept, got %v", got.ApiKey)
+		}
+	})
+
+	t.Run("Search snippets", func(t *testing.T) {
+		resp := &applicantsv1.SearchApplicantsResponse{
+			Results: []*applicantsv1.SearchResult{
+				{Applicant: &applicantsv1.JobApplicant{Id: 1}, Rank: 0.5, Snippet: "<mark>Ada</mark> Lovelace"},
+			},
+		}
+
+		got := Redact(resp).(*applicantsv1.SearchApplicantsResponse)
+		if snippet := got.Results[0].Snippet; snippet != logging.Redacted {
+			t.Errorf("Expected snippet to be redacted, got %q", snippet)
+		}
+		if got.Results[0].Rank != 0.5 {
+			t.Errorf("Expected rank to be kept, got %v", got.Results[0].Rank)
+		}
+	})
+
+	t.Run("Sensitive message field is cleared", func(t *testing.T) {
+		changes, err := structpb.NewStruct(map[string]interface{}{"email": "a@example.com"})
+		if err != nil {
+			t.Fatalf("failed to build struct: %v", err)
+		}
+
+		got := Redact(&applicantsv1.AuditEvent{Id: 1, Changes: changes}).(*applicantsv1.AuditEvent)
+		if got.Changes != nil {
+			t.Errorf("Expected changes to be cleared, got %v", got.Changes)
+		}
+	})
+
+	t.Run("Empty fields stay empty", func(t *testing.T) {
+		got := Redact(&applicantsv1.UpdateApplicantRequest{Id: 1})
+		if !proto.Equal(got, &applicantsv1.UpdateApplicantRequest{Id: 1}) {
+			t.Errorf("Expected unset sensitive fields to stay unset, got %v", got)
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Log formats selectable in Config
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// Redacted replaces the value of sensitive strings in logged payloads and
+// audit records
+const Redacted = "[REDACTED]"
+
+// Config selects how log lines are written
+type Config struct {
+	// Level is the minimum level logged: debug, info, warn, error, dpanic, panic or fatal
+	Level string
+
+	// Format is FormatJSON or FormatConsole
+	Format string
+
+	// Sampling keeps the first 100 identical messages per second and every
+	// 100th after that
+	Sampling bool
+
+	// Caller annotates log lines with the file and line they were logged from
+	Caller bool
+
+	// StacktraceLevel is the minimum level log lines carry a stack trace at
+	StacktraceLevel string
+}
+
+// New builds a logger from cfg. The returned level changes the minimum level
+// of the logger at runtime; it serves GET and PUT requests as an http.Handler.
+func New(cfg Config) (*zap.Logger, zap.AtomicLevel, error) {
+	level, err := zap.ParseAtomicLevel(cfg.Level)
+	if err != nil {
+		return nil, zap.AtomicLevel{}, fmt.Errorf("invalid log level: %w", err)
+	}
+
+	stacktraceLevel, err := zapcore.ParseLevel(cfg.StacktraceLevel)
+	if err != nil {
+		return nil, zap.AtomicLevel{}, fmt.Errorf("invalid stacktrace level: %w", err)
+	}
+
+	var encoderConfig zapcore.EncoderConfig
+	switch cfg.Format {
+	case FormatJSON:
+		encoderConfig = zap.NewProductionEncoderConfig()
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	case FormatConsole:
+		encoderConfig = zap.NewDevelopmentEncoderConfig()
+	default:
+		return nil, zap.AtomicLevel{}, fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+
+	zapConfig := zap.Config{
+		Level:             level,
+		Encoding:          cfg.Format,
+		EncoderConfig:     encoderConfig,
+		OutputPaths:       []string{"stderr"},
+		ErrorOutputPaths:  []string{"stderr"},
+		DisableCaller:     !cfg.Caller,
+		DisableStacktrace: true,
+	}
+	if cfg.Sampling {
+		zapConfig.Sampling = &zap.SamplingConfig{Initial: 100, Thereafter: 100}
+	}
+
+	logger, err := zapConfig.Build(zap.AddStacktrace(stacktraceLevel))
+	if err != nil {
+		return nil, zap.AtomicLevel{}, fmt.Errorf("failed to build logger: %w", err)
+	}
+	return logger, level, nil
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "JSON", cfg: Config{Level: "info", Format: FormatJSON, StacktraceLevel: "error"}},
+		{name: "Console with sampling", cfg: Config{Level: "debug", Format: FormatConsole, Sampling: true, Caller: true, StacktraceLevel: "warn"}},
+		{name: "Unknown level", cfg: Config{Level: "verbose", Format: FormatJSON, StacktraceLevel: "error"}, wantErr: true},
+		{name: "Unknown format", cfg: Config{Level: "info", Format: "logfmt", StacktraceLevel: "error"}, wantErr: true},
+		{name: "Unknown stacktrace level", cfg: Config{Level: "info", Format: FormatJSON, StacktraceLevel: "never"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _, err := New(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && logger == nil {
+				t.Error("Expected a logger")
+			}
+		})
+	}
+}
+
+func TestNew_RuntimeLevel(t *testing.T) {
+	logger, level, err := New(Config{Level: "info", Format: FormatJSON, StacktraceLevel: "error"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if logger.Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("Expected debug to be disabled at info level")
+	}
+
+	w := httptest.NewRecorder()
+	level.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"level":"debug"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !logger.Core().Enabled(zapcore.DebugLevel) {
+		t.Error("Expected debug to be enabled after changing the level")
+	}
+}
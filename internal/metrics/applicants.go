@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+)
+
+const (
+	// statsTimeout bounds the database query refreshing the applicant metrics
+	statsTimeout = 5 * time.Second
+
+	// statsRefreshInterval is how often Run refreshes the applicant metrics
+	statsRefreshInterval = 30 * time.Second
+)
+
+// ApplicantStore is the part of sqlc.Querier used to gather applicant metrics
+type ApplicantStore interface {
+	CountApplicantsByStatus(ctx context.Context) ([]sqlc.CountApplicantsByStatusRow, error)
+}
+
+// ApplicantCollector exports business metrics about the stored applicants.
+// They are queried from the database by Refresh, not on every scrape, so
+// scrapes cannot add load to the database.
+type ApplicantCollector struct {
+	queries ApplicantStore
+	logger  *zap.Logger
+
+	applicants   *prometheus.Desc
+	averageScore *prometheus.Desc
+
+	mu        sync.RWMutex
+	refreshed bool
+	counts    map[applicantsv1.ApplicantStatus]int64
+	average   float64
+}
+
+// NewApplicantCollector creates an ApplicantCollector
+func NewApplicantCollector(queries ApplicantStore, logger *zap.Logger) *ApplicantCollector {
+	return &ApplicantCollector{
+		queries: queries,
+		logger:  logger,
+		applicants: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "by_status"),
+			"Applicants that are not deleted, by status.",
+			[]string{"status"}, nil,
+		),
+		averageScore: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "overall_score_average"),
+			"Average overall score of the applicants that are not deleted.",
+			nil, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *ApplicantCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.applicants
+	ch <- c.averageScore
+}
+
+// Run refreshes the metrics right away and then periodically until ctx is done
+func (c *ApplicantCollector) Run(ctx context.Context) {
+	ticker := time.NewTicker(statsRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Refresh(ctx); err != nil {
+			c.logger.Warn("failed to refresh applicant metrics", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh queries the applicant metrics reported by Collect. On error the
+// previously queried metrics are kept.
+func (c *ApplicantCollector) Refresh(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, statsTimeout)
+	defer cancel()
+
+	rows, err := c.queries.CountApplicantsByStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[applicantsv1.ApplicantStatus]int64)
+	var total int64
+	var scoreSum float64
+	for _, row := range rows {
+		counts[applicantsv1.ApplicantStatus(row.Status)] += row.Applicants
+		total += row.Applicants
+		scoreSum += row.AvgScore * float64(row.Applicants)
+	}
+
+	var average float64
+	if total > 0 {
+		average = scoreSum / float64(total)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshed = true
+	c.counts = counts
+	c.average = average
+	return nil
+}
+
+// Collect implements prometheus.Collector, reporting the metrics of the last
+// successful Refresh and nothing before it. Every known status is reported,
+// with 0 for statuses without applicants, so series do not disappear.
+func (c *ApplicantCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.refreshed {
+		return
+	}
+
+	for value := range applicantsv1.ApplicantStatus_name {
+		status := applicantsv1.ApplicantStatus(value)
+		if status == applicantsv1.ApplicantStatus_APPLICANT_STATUS_UNSPECIFIED {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.applicants, prometheus.GaugeValue, float64(c.counts[status]), statusLabel(status))
+	}
+	ch <- prometheus.MustNewConstMetric(c.averageScore, prometheus.GaugeValue, c.average)
+}
+
+// statusLabel turns APPLICANT_STATUS_REVIEWING into "reviewing"
+func statusLabel(status applicantsv1.ApplicantStatus) string {
+	return strings.ToLower(strings.TrimPrefix(status.String(), "APPLICANT_STATUS_"))
+}
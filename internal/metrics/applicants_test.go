@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+)
+
+// fakeApplicantStore returns fixed status counts
+type fakeApplicantStore struct {
+	rows  []sqlc.CountApplicantsByStatusRow
+	err   error
+	calls int
+}
+
+func (f *fakeApplicantStore) CountApplicantsByStatus(ctx context.Context) ([]sqlc.CountApplicantsByStatusRow, error) {
+	f.calls++
+	return f.rows, f.err
+}
+
+func TestApplicantCollector(t *testing.T) {
+	t.Run("Counts per status and average score", func(t *testing.T) {
+		collector := NewApplicantCollector(&fakeApplicantStore{rows: []sqlc.CountApplicantsByStatusRow{
+			{Status: int32(applicantsv1.ApplicantStatus_APPLICANT_STATUS_APPLIED), Applicants: 3, AvgScore: 60},
+			{Status: int32(applicantsv1.ApplicantStatus_APPLICANT_STATUS_HIRED), Applicants: 1, AvgScore: 100},
+		}}, zap.NewNop())
+		if err := collector.Refresh(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		expected := `
+# HELP applicants_by_status Applicants that are not deleted, by status.
+# TYPE applicants_by_status gauge
+applicants_by_status{status="applied"} 3
+applicants_by_status{status="hired"} 1
+applicants_by_status{status="interviewed"} 0
+applicants_by_status{status="obviously_the_best"} 0
+applicants_by_status{status="rejected"} 0
+applicants_by_status{status="reviewing"} 0
+# HELP applicants_overall_score_average Average overall score of the applicants that are not deleted.
+# TYPE applicants_overall_score_average gauge
+applicants_overall_score_average 70
+`
+		if err := testutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Nothing is reported before the first refresh", func(t *testing.T) {
+		store := &fakeApplicantStore{err: errors.New("connection refused")}
+		collector := NewApplicantCollector(store, zap.NewNop())
+		if err := collector.Refresh(context.Background()); err == nil {
+			t.Fatal("Expected the database error")
+		}
+		if n := testutil.CollectAndCount(collector); n != 0 {
+			t.Errorf("Expected no metrics, got %d", n)
+		}
+	})
+
+	t.Run("Scrapes do not query the database", func(t *testing.T) {
+		store := &fakeApplicantStore{rows: []sqlc.CountApplicantsByStatusRow{
+			{Status: int32(applicantsv1.ApplicantStatus_APPLICANT_STATUS_APPLIED), Applicants: 2, AvgScore: 50},
+		}}
+		collector := NewApplicantCollector(store, zap.NewNop())
+		if err := collector.Refresh(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		// A failed refresh keeps the last metrics
+		store.err = errors.New("connection refused")
+		if err := collector.Refresh(context.Background()); err == nil {
+			t.Fatal("Expected the database error")
+		}
+
+		expected := `
+# HELP applicants_overall_score_average Average overall score of the applicants that are not deleted.
+# TYPE applicants_overall_score_average gauge
+applicants_overall_score_average 50
+`
+		for i := 0; i < 3; i++ {
+			if err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "applicants_overall_score_average"); err != nil {
+				t.Error(err)
+			}
+		}
+		if store.calls != 2 {
+			t.Errorf("Expected only the 2 refreshes to query the database, got %d queries", store.calls)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+)
+
+// namespace prefixes the names of the service's own metrics
+const namespace = "applicants"
+
+// Metrics holds the Prometheus collectors of the service and the registry
+// they are exposed from
+type Metrics struct {
+	registry *prometheus.Registry
+
+	grpcRequests *prometheus.CounterVec
+	grpcDuration *prometheus.HistogramVec
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+}
+
+// New creates the service metrics together with the Go runtime and process
+// collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "gRPC requests handled, by full method name and status code.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "Time to handle gRPC requests, by full method name and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "REST gateway requests handled, by HTTP method, route template and status code.",
+		}, []string{"method", "route", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time to handle REST gateway requests, by HTTP method and route template.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.grpcRequests,
+		m.grpcDuration,
+		m.httpRequests,
+		m.httpDuration,
+	)
+	return m
+}
+
+// RegisterDB exposes the connection pool statistics of db
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Register adds a collector to the registry
+func (m *Metrics) Register(c prometheus.Collector) error {
+	return m.registry.Register(c)
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveGRPC records a handled gRPC request
+func (m *Metrics) ObserveGRPC(method string, code codes.Code, duration time.Duration) {
+	m.grpcRequests.WithLabelValues(method, code.String()).Inc()
+	m.grpcDuration.WithLabelValues(method, code.String()).Observe(duration.Seconds())
+}
+
+// ObserveHTTP records a handled REST gateway request. route is the path
+// template, e.g. /v1/applicants/{id=*}, so IDs do not create new series.
+func (m *Metrics) ObserveHTTP(method, route string, status int, duration time.Duration) {
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+)
+
+func TestMetrics(t *testing.T) {
+	m := New()
+
+	m.ObserveGRPC("/applicants.v1.ApplicantsService/GetApplicant", codes.OK, 10*time.Millisecond)
+	m.ObserveGRPC("/applicants.v1.ApplicantsService/GetApplicant", codes.NotFound, 5*time.Millisecond)
+	m.ObserveGRPC("/applicants.v1.ApplicantsService/GetApplicant", codes.NotFound, 5*time.Millisecond)
+	m.ObserveHTTP(http.MethodGet, "/v1/applicants/{id}", http.StatusNotFound, 7*time.Millisecond)
+
+	if got := testutil.ToFloat64(m.grpcRequests.WithLabelValues("/applicants.v1.ApplicantsService/GetApplicant", "NotFound")); got != 2 {
+		t.Errorf("Expected 2 NotFound requests, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.httpRequests.WithLabelValues(http.MethodGet, "/v1/applicants/{id}", "404")); got != 1 {
+		t.Errorf("Expected 1 gateway request, got %v", got)
+	}
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(w.Body)
+	for _, name := range []string{
+		"applicants_grpc_request_duration_seconds_bucket",
+		"applicants_http_request_duration_seconds_bucket",
+		"go_goroutines",
+	} {
+		if !strings.Contains(string(body), name) {
+			t.Errorf("Expected %s to be exposed", name)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Thrun12/golang-assignment/internal/auth"
+)
+
+// apiKeyMetadataKey is the metadata key carrying API keys
+const apiKeyMetadataKey = "x-api-key"
+
+// AuthInterceptor returns a gRPC unary server interceptor that requires a valid
+// JWT bearer token in the authorization metadata, or an API key in the
+// x-api-key metadata, and stores the caller's principal in the request context.
+// The gateway forwards the HTTP Authorization and X-API-Key headers as this
+// metadata; its health and docs endpoints bypass gRPC and stay public.
+func AuthInterceptor(authenticator *auth.Authenticator, apiKeys *auth.APIKeyAuthenticator, logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		ctx, err := authenticate(ctx, info.FullMethod, authenticator, apiKeys, logger)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthStreamInterceptor is the streaming counterpart of AuthInterceptor; the
+// caller is authenticated once when the stream is opened. Server reflection
+// stays public.
+func AuthStreamInterceptor(authenticator *auth.Authenticator, apiKeys *auth.APIKeyAuthenticator, logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if isReflection(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx, err := authenticate(ss.Context(), info.FullMethod, authenticator, apiKeys, logger)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate verifies the API key or bearer token of a call to method and
+// returns ctx with the caller's principal
+func authenticate(ctx context.Context, method string, authenticator *auth.Authenticator, apiKeys *auth.APIKeyAuthenticator, logger *zap.Logger) (context.Context, error) {
+	if key, ok := apiKey(ctx); ok {
+		principal, err := apiKeys.Authenticate(ctx, key)
+		if errors.Is(err, auth.ErrInvalidAPIKey) {
+			logger.Debug("API key authentication failed", zap.String("method", method))
+			return nil, status.Error(codes.Unauthenticated, "invalid, revoked or expired API key")
+		}
+		if err != nil {
+			logger.Error("failed to verify API key", zap.String("method", method), zap.Error(err))
+			return nil, status.Error(codes.Unavailable, "failed to verify API key")
+		}
+		return auth.NewContext(ctx, principal), nil
+	}
+
+	token, err := bearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	principal, err := authenticator.Authenticate(token)
+	if err != nil {
+		logger.Debug("authentication failed",
+			zap.String("method", method),
+			zap.Error(err),
+		)
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	}
+
+	return auth.NewContext(ctx, principal), nil
+}
+
+// apiKey returns the key in the x-api-key metadata, if any
+func apiKey(ctx context.Context) (string, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(apiKeyMetadataKey)
+	if len(values) == 0 || strings.TrimSpace(values[0]) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(values[0]), true
+}
+
+// bearerToken extracts the token from the "authorization: Bearer <token>" metadata
+func bearerToken(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	scheme, token, ok := strings.Cut(values[0], " ")
+	token = strings.TrimSpace(token)
+	if !ok || !strings.EqualFold(scheme, "bearer") || token == "" {
+		return "", status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+	}
+	return token, nil
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Thrun12/golang-assignment/internal/auth"
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+)
+
+func TestAuthInterceptor(t *testing.T) {
+	const secret = "test-secret-that-is-at-least-32-bytes"
+
+	authenticator, err := auth.NewAuthenticator(auth.Config{HS256Secret: secret})
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %v", err)
+	}
+	apiKeys := auth.NewAPIKeyAuthenticator(fakeAPIKeyStore{
+		auth.HashAPIKey("ak_valid"): {ID: 7, Owner: "recruiter@example.com", Scopes: []string{"recruiter"}},
+	}, zap.NewNop())
+	interceptor := AuthInterceptor(authenticator, apiKeys, zap.NewNop())
+	info := &grpc.UnaryServerInfo{FullMethod: "/applicants.v1.ApplicantsService/ListApplicants"}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "recruiter@example.com",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		apiKey        string
+		expectedCode  codes.Code
+	}{
+		{name: "Valid bearer token", authorization: "Bearer " + token, expectedCode: codes.OK},
+		{name: "Lowercase scheme", authorization: "bearer " + token, expectedCode: codes.OK},
+		{name: "Missing header", expectedCode: codes.Unauthenticated},
+		{name: "Wrong scheme", authorization: "Basic dXNlcjpwYXNz", expectedCode: codes.Unauthenticated},
+		{name: "Invalid token", authorization: "Bearer not-a-jwt", expectedCode: codes.Unauthenticated},
+		{name: "Valid API key", apiKey: "ak_valid", expectedCode: codes.OK},
+		{name: "Unknown API key", apiKey: "ak_unknown", expectedCode: codes.Unauthenticated},
+		{name: "API key takes precedence", authorization: "Bearer " + token, apiKey: "ak_unknown", expectedCode: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			md := metadata.MD{}
+			if tt.authorization != "" {
+				md.Set("authorization", tt.authorization)
+			}
+			if tt.apiKey != "" {
+				md.Set("x-api-key", tt.apiKey)
+			}
+			ctx = metadata.NewIncomingContext(ctx, md)
+
+			var principal *auth.Principal
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				principal, _ = auth.FromContext(ctx)
+				return nil, nil
+			}
+
+			_, err := interceptor(ctx, nil, info, handler)
+			if code := status.Code(err); code != tt.expectedCode {
+				t.Fatalf("Expected %s, got %v", tt.expectedCode, err)
+			}
+			if tt.expectedCode == codes.OK && (principal == nil || principal.Subject != "recruiter@example.com") {
+				t.Errorf("Expected principal in context, got %+v", principal)
+			}
+		})
+	}
+}
+
+// fakeAPIKeyStore holds active API keys by hash
+type fakeAPIKeyStore map[string]sqlc.ApiKey
+
+func (f fakeAPIKeyStore) GetActiveApiKeyByHash(ctx context.Context, keyHash string) (sqlc.ApiKey, error) {
+	key, ok := f[keyHash]
+	if !ok {
+		return sqlc.ApiKey{}, sql.ErrNoRows
+	}
+	return key, nil
+}
+
+func (f fakeAPIKeyStore) TouchApiKey(ctx context.Context, id int64) error {
+	return nil
+}
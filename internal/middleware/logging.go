@@ -4,15 +4,25 @@ import (
 	"context"
 	"time"
 
-	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+
+	"github.com/Thrun12/golang-assignment/internal/logging/calllog"
+	"github.com/Thrun12/golang-assignment/internal/requestid"
 )
 
-// UnaryServerInterceptor returns a gRPC unary server interceptor for logging
-func UnaryServerInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+// UnaryServerInterceptor returns a gRPC unary server interceptor for logging.
+// It takes the request ID from the x-request-id metadata, or generates one,
+// stores it in the context for the handlers and returns it as header metadata.
+// Completed calls are logged at the level policy sets for the method, with the
+// redacted request and response if the policy asks for payloads, and at Warn
+// or above when slower than the method's threshold. Failed calls are logged at
+// Warn, or at Error when the server is at fault.
+func UnaryServerInterceptor(logger *zap.Logger, policy *calllog.Policy) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req interface{},
@@ -20,12 +30,15 @@ func UnaryServerInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
 		start := time.Now()
+		rule := policy.Rule(info.FullMethod)
 
-		// Generate request ID
-		requestID := uuid.New().String()
+		// Honour the caller's request ID so logs can be correlated across services
+		requestID := incomingRequestID(ctx)
+		ctx = requestid.NewContext(ctx, requestID)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestid.Header, requestID))
 
 		// Log request
-		logger.Info("gRPC request started",
+		logger.Debug("gRPC request started",
 			zap.String("method", info.FullMethod),
 			zap.String("request_id", requestID),
 		)
@@ -35,26 +48,115 @@ func UnaryServerInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
 
 		// Calculate duration
 		duration := time.Since(start)
+		slow := duration > rule.SlowThreshold
 
 		// Log response
+		level := rule.Level
+		msg := "gRPC request completed"
+		code := codes.OK
+		if err != nil {
+			level = failureLevel(status.Code(err))
+			msg = "gRPC request failed"
+			code = status.Code(err)
+		}
+		if slow && level < zapcore.WarnLevel {
+			level = zapcore.WarnLevel
+		}
+
+		ce := logger.Check(level, msg)
+		if ce == nil {
+			return resp, err
+		}
+		fields := []zap.Field{
+			zap.String("method", info.FullMethod),
+			zap.String("request_id", requestID),
+			zap.Duration("duration", duration),
+			zap.String("code", code.String()),
+		}
+		if slow {
+			fields = append(fields, zap.Bool("slow", true))
+		}
 		if err != nil {
-			st, _ := status.FromError(err)
-			logger.Error("gRPC request failed",
-				zap.String("method", info.FullMethod),
-				zap.String("request_id", requestID),
-				zap.Duration("duration", duration),
-				zap.String("code", st.Code().String()),
-				zap.Error(err),
-			)
-		} else {
-			logger.Info("gRPC request completed",
-				zap.String("method", info.FullMethod),
-				zap.String("request_id", requestID),
-				zap.Duration("duration", duration),
-				zap.String("code", codes.OK.String()),
-			)
+			fields = append(fields, zap.Error(err))
 		}
+		if rule.Payloads {
+			fields = append(fields, calllog.Payload("request", req))
+			if err == nil {
+				fields = append(fields, calllog.Payload("response", resp))
+			}
+		}
+		ce.Write(fields...)
 
 		return resp, err
 	}
 }
+
+// StreamServerInterceptor is the streaming counterpart of
+// UnaryServerInterceptor. The stream is logged when it ends, at the level the
+// policy sets for the method; payloads are not logged and streams are never
+// reported as slow, since they stay open for as long as the client listens.
+func StreamServerInterceptor(logger *zap.Logger, policy *calllog.Policy) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := time.Now()
+		rule := policy.Rule(info.FullMethod)
+
+		requestID := incomingRequestID(ss.Context())
+		ctx := requestid.NewContext(ss.Context(), requestID)
+		_ = ss.SetHeader(metadata.Pairs(requestid.Header, requestID))
+
+		logger.Debug("gRPC stream started",
+			zap.String("method", info.FullMethod),
+			zap.String("request_id", requestID),
+		)
+
+		err := handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+
+		level := rule.Level
+		msg := "gRPC stream completed"
+		if err != nil {
+			level = failureLevel(status.Code(err))
+			msg = "gRPC stream failed"
+		}
+
+		ce := logger.Check(level, msg)
+		if ce == nil {
+			return err
+		}
+		fields := []zap.Field{
+			zap.String("method", info.FullMethod),
+			zap.String("request_id", requestID),
+			zap.Duration("duration", time.Since(start)),
+			zap.String("code", status.Code(err).String()),
+		}
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		}
+		ce.Write(fields...)
+
+		return err
+	}
+}
+
+// failureLevel returns the level a call failing with code is logged at: Error
+// when the server is at fault, Warn when the request or the caller is
+func failureLevel(code codes.Code) zapcore.Level {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.DataLoss, codes.Unavailable, codes.Unimplemented, codes.DeadlineExceeded:
+		return zapcore.ErrorLevel
+	}
+	return zapcore.WarnLevel
+}
+
+// incomingRequestID returns the valid request ID sent by the caller, or a new one
+func incomingRequestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if ids := md.Get(requestid.Header); len(ids) > 0 && requestid.Valid(ids[0]) {
+		return ids[0]
+	}
+	return requestid.New()
+}
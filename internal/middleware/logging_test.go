@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/logging"
+	"github.com/Thrun12/golang-assignment/internal/logging/calllog"
+	"github.com/Thrun12/golang-assignment/internal/requestid"
+)
+
+func TestUnaryServerInterceptor_RequestID(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: applicantsv1.ApplicantsService_GetApplicant_FullMethodName}
+
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{name: "Caller's ID is kept", incoming: "req-abc-123", keep: true},
+		{name: "Missing ID is generated"},
+		{name: "Invalid ID is replaced", incoming: "has spaces in it"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.InfoLevel)
+			interceptor := UnaryServerInterceptor(zap.New(core), calllog.DefaultPolicy())
+
+			ctx := context.Background()
+			if tt.incoming != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(requestid.Header, tt.incoming))
+			}
+			stream := &headerStream{}
+			ctx = grpc.NewContextWithServerTransportStream(ctx, stream)
+
+			var handled string
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				handled = requestid.FromContext(ctx)
+				return "ok", nil
+			}
+			if _, err := interceptor(ctx, nil, info, handler); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if !requestid.Valid(handled) {
+				t.Fatalf("Expected a valid request ID in the handler context, got %q", handled)
+			}
+			if tt.keep && handled != tt.incoming {
+				t.Errorf("Expected request ID %q, got %q", tt.incoming, handled)
+			}
+			if !tt.keep && handled == tt.incoming {
+				t.Errorf("Expected a generated request ID, got %q", handled)
+			}
+			if got := stream.header.Get(requestid.Header); len(got) != 1 || got[0] != handled {
+				t.Errorf("Expected request ID %q in response header, got %v", handled, got)
+			}
+			for _, entry := range logs.All() {
+				if entry.ContextMap()["request_id"] != handled {
+					t.Errorf("Expected %q to be logged with request ID %q, got %v", entry.Message, handled, entry.ContextMap()["request_id"])
+				}
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor_Policy(t *testing.T) {
+	policy, err := calllog.NewPolicy(calllog.File{
+		Default: calllog.Method{Level: "info", SlowThreshold: time.Hour},
+		Methods: map[string]calllog.Method{
+			applicantsv1.ApplicantsService_GetBestApplicant_FullMethodName: {Level: "debug"},
+			applicantsv1.ApplicantsService_CreateApplicant_FullMethodName:  {Payloads: proto.Bool(true)},
+			applicantsv1.ApplicantsService_RecomputeScores_FullMethodName:  {SlowThreshold: time.Nanosecond},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create policy: %v", err)
+	}
+
+	req := &applicantsv1.CreateApplicantRequest{Name: "Ada Lovelace", Email: "ada@example.com"}
+	tests := []struct {
+		name      string
+		method    string
+		err       error
+		wantLevel zapcore.Level
+		wantLog   bool
+		wantSlow  bool
+	}{
+		{name: "Default level", method: applicantsv1.ApplicantsService_GetApplicant_FullMethodName, wantLevel: zapcore.InfoLevel, wantLog: true},
+		{name: "Debug method is not logged at info", method: applicantsv1.ApplicantsService_GetBestApplicant_FullMethodName},
+		{name: "Client error", method: applicantsv1.ApplicantsService_GetApplicant_FullMethodName, err: status.Error(codes.NotFound, "not found"), wantLevel: zapcore.WarnLevel, wantLog: true},
+		{name: "Server error", method: applicantsv1.ApplicantsService_GetApplicant_FullMethodName, err: status.Error(codes.Internal, "boom"), wantLevel: zapcore.ErrorLevel, wantLog: true},
+		{name: "Slow call", method: applicantsv1.ApplicantsService_RecomputeScores_FullMethodName, wantLevel: zapcore.WarnLevel, wantLog: true, wantSlow: true},
+		{name: "Payloads", method: applicantsv1.ApplicantsService_CreateApplicant_FullMethodName, wantLevel: zapcore.InfoLevel, wantLog: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.InfoLevel)
+			interceptor := UnaryServerInterceptor(zap.New(core), policy)
+
+			ctx := grpc.NewContextWithServerTransportStream(context.Background(), &headerStream{})
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &applicantsv1.CreateApplicantResponse{Applicant: &applicantsv1.JobApplicant{Id: 1, Email: "ada@example.com"}}, nil
+			}
+			_, _ = interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+
+			entries := logs.All()
+			if !tt.wantLog {
+				if len(entries) != 0 {
+					t.Errorf("Expected no log entries, got %d", len(entries))
+				}
+				return
+			}
+			if len(entries) != 1 {
+				t.Fatalf("Expected 1 log entry, got %d", len(entries))
+			}
+
+			entry := entries[0]
+			if entry.Level != tt.wantLevel {
+				t.Errorf("Expected level %s, got %s", tt.wantLevel, entry.Level)
+			}
+			if slow, _ := entry.ContextMap()["slow"].(bool); slow != tt.wantSlow {
+				t.Errorf("Expected slow=%v, got %v", tt.wantSlow, slow)
+			}
+
+			payloads := fmt.Sprintf("%s %s", entry.ContextMap()["request"], entry.ContextMap()["response"])
+			if tt.method != applicantsv1.ApplicantsService_CreateApplicant_FullMethodName {
+				if _, ok := entry.ContextMap()["request"]; ok {
+					t.Error("Expected no request payload")
+				}
+				return
+			}
+			if !strings.Contains(payloads, "Ada Lovelace") || !strings.Contains(payloads, logging.Redacted) {
+				t.Errorf("Expected redacted payloads, got %s", payloads)
+			}
+			if strings.Contains(payloads, "ada@example.com") {
+				t.Errorf("Expected email to be redacted, got %s", payloads)
+			}
+		})
+	}
+}
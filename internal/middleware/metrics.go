@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/Thrun12/golang-assignment/internal/metrics"
+)
+
+// MetricsInterceptor returns a gRPC unary server interceptor recording the
+// rate, errors and duration of every call by method and status code. It should
+// be first in the chain so rejected and recovered calls are counted too.
+func MetricsInterceptor(m *metrics.Metrics) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.ObserveGRPC(info.FullMethod, status.Code(err), time.Since(start))
+		return resp, err
+	}
+}
+
+// MetricsStreamInterceptor is the streaming counterpart of MetricsInterceptor.
+// The recorded duration is the lifetime of the stream.
+func MetricsStreamInterceptor(m *metrics.Metrics) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.ObserveGRPC(info.FullMethod, status.Code(err), time.Since(start))
+		return err
+	}
+}
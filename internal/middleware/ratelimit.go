@@ -0,0 +1,204 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/Thrun12/golang-assignment/internal/auth"
+	"github.com/Thrun12/golang-assignment/internal/ratelimit"
+)
+
+// rateLimitedReason is the ErrorInfo reason reported for rejected calls
+const rateLimitedReason = "RATE_LIMITED"
+
+// RateLimitInterceptor returns a gRPC unary server interceptor that charges
+// every call to a token bucket of its caller: the API key, the token subject,
+// or the client IP for anonymous callers. Calls over budget are rejected with
+// ResourceExhausted and a RetryInfo detail; every response carries the
+// x-ratelimit-* header metadata describing the remaining budget.
+func RateLimitInterceptor(limiter *ratelimit.Limiter, logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if err := charge(ctx, info.FullMethod, limiter, logger); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// RateLimitStreamInterceptor is the streaming counterpart of
+// RateLimitInterceptor; opening a stream is charged as one call
+func RateLimitStreamInterceptor(limiter *ratelimit.Limiter, logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if err := charge(ss.Context(), info.FullMethod, limiter, logger); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// IPRateLimitInterceptor returns a gRPC unary server interceptor that charges
+// every call to the per-IP budget of its client address. It runs ahead of
+// AuthInterceptor, so callers without valid credentials are limited too and
+// cannot make the server verify credentials without bound.
+func IPRateLimitInterceptor(limiter *ratelimit.Limiter, logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if err := chargeIP(ctx, info.FullMethod, limiter, logger); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// IPRateLimitStreamInterceptor is the streaming counterpart of
+// IPRateLimitInterceptor
+func IPRateLimitStreamInterceptor(limiter *ratelimit.Limiter, logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if err := chargeIP(ss.Context(), info.FullMethod, limiter, logger); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// chargeIP charges a call to method to the budget of its client address and
+// returns a ResourceExhausted status if the address is over budget. Allowed
+// calls leave the x-ratelimit-* headers to the caller's own budget.
+func chargeIP(ctx context.Context, method string, limiter *ratelimit.Limiter, logger *zap.Logger) error {
+	ip := clientIP(ctx)
+	decision := limiter.AllowIP(ip)
+	if decision.Allowed {
+		return nil
+	}
+
+	setRateLimitHeaders(ctx, decision)
+	logger.Debug("per-IP rate limit exceeded",
+		zap.String("method", method),
+		zap.String("ip", ip),
+		zap.Duration("retry_after", decision.RetryAfter),
+	)
+	return rateLimited(method, decision.RetryAfter)
+}
+
+// charge charges a call to method to its caller's budget, sets the
+// x-ratelimit-* header metadata and returns a ResourceExhausted status if the
+// caller is over budget
+func charge(ctx context.Context, method string, limiter *ratelimit.Limiter, logger *zap.Logger) error {
+	client := clientKey(ctx)
+	decision := limiter.Allow(client, method)
+
+	setRateLimitHeaders(ctx, decision)
+
+	if !decision.Allowed {
+		logger.Debug("rate limit exceeded",
+			zap.String("method", method),
+			zap.String("client", client),
+			zap.Duration("retry_after", decision.RetryAfter),
+		)
+		return rateLimited(method, decision.RetryAfter)
+	}
+	return nil
+}
+
+// setRateLimitHeaders sets the x-ratelimit-* header metadata describing the
+// budget decision was made on
+func setRateLimitHeaders(ctx context.Context, decision ratelimit.Decision) {
+	_ = grpc.SetHeader(ctx, metadata.Pairs(
+		ratelimit.LimitHeader, strconv.Itoa(decision.Limit),
+		ratelimit.RemainingHeader, strconv.Itoa(decision.Remaining),
+		ratelimit.ResetHeader, strconv.Itoa(ceilSeconds(decision.Reset)),
+	))
+}
+
+// clientKey identifies the caller a call is charged to
+func clientKey(ctx context.Context) string {
+	if principal, ok := auth.FromContext(ctx); ok {
+		if principal.APIKeyID != 0 {
+			return fmt.Sprintf("api_key:%d", principal.APIKeyID)
+		}
+		if principal.Subject != "" {
+			return "subject:" + principal.Subject
+		}
+	}
+	return "ip:" + clientIP(ctx)
+}
+
+// clientIP returns the address of the caller. Calls relayed by the REST gateway
+// over loopback are attributed to the address the gateway appended to the
+// x-forwarded-for metadata, so REST clients do not share one budget.
+func clientIP(ctx context.Context) string {
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+
+	if parsed := net.ParseIP(ip); parsed != nil && parsed.IsLoopback() {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if forwarded := md.Get("x-forwarded-for"); len(forwarded) > 0 {
+			hops := strings.Split(forwarded[len(forwarded)-1], ",")
+			if last := strings.TrimSpace(hops[len(hops)-1]); last != "" {
+				return last
+			}
+		}
+	}
+	return ip
+}
+
+// ceilSeconds rounds d up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// rateLimited builds a ResourceExhausted status telling the caller when to retry
+func rateLimited(method string, retryAfter time.Duration) error {
+	msg := fmt.Sprintf("rate limit exceeded, retry in %ds", ceilSeconds(retryAfter))
+
+	st, err := status.New(codes.ResourceExhausted, msg).WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)},
+		&errdetails.ErrorInfo{
+			Reason:   rateLimitedReason,
+			Domain:   "applicants.v1",
+			Metadata: map[string]string{"method": method},
+		},
+	)
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, msg)
+	}
+	return st.Err()
+}
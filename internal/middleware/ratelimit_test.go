@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/auth"
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+	"github.com/Thrun12/golang-assignment/internal/ratelimit"
+)
+
+// headerStream records the header metadata set by an interceptor
+type headerStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *headerStream) Method() string { return "" }
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: applicantsv1.ApplicantsService_GetApplicant_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	policy, err := ratelimit.NewPolicy(ratelimit.File{Default: ratelimit.Limit{RequestsPerSecond: 1, Burst: 1}})
+	if err != nil {
+		t.Fatalf("Failed to create policy: %v", err)
+	}
+
+	t.Run("Over budget", func(t *testing.T) {
+		interceptor := RateLimitInterceptor(ratelimit.NewLimiter(policy), zap.NewNop())
+		ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "script"})
+
+		stream := &headerStream{}
+		if _, err := interceptor(grpc.NewContextWithServerTransportStream(ctx, stream), nil, info, handler); err != nil {
+			t.Fatalf("Expected first call to succeed, got: %v", err)
+		}
+		if got := stream.header.Get(ratelimit.RemainingHeader); len(got) != 1 || got[0] != "0" {
+			t.Errorf("Expected remaining budget 0, got %v", got)
+		}
+
+		_, err := interceptor(ctx, nil, info, handler)
+		st := status.Convert(err)
+		if st.Code() != codes.ResourceExhausted {
+			t.Fatalf("Expected ResourceExhausted, got %v", err)
+		}
+		var retryInfo *errdetails.RetryInfo
+		for _, detail := range st.Details() {
+			if d, ok := detail.(*errdetails.RetryInfo); ok {
+				retryInfo = d
+			}
+		}
+		if retryInfo == nil || retryInfo.GetRetryDelay().AsDuration() <= 0 {
+			t.Errorf("Expected RetryInfo with a positive delay, got %v", st.Details())
+		}
+
+		// Another caller has its own budget
+		other := auth.NewContext(context.Background(), &auth.Principal{Subject: "other", APIKeyID: 7})
+		if _, err := interceptor(other, nil, info, handler); err != nil {
+			t.Errorf("Expected other caller to be allowed, got: %v", err)
+		}
+	})
+
+	t.Run("Gateway clients are told apart by forwarded address", func(t *testing.T) {
+		interceptor := RateLimitInterceptor(ratelimit.NewLimiter(policy), zap.NewNop())
+
+		call := func(forwardedFor string) error {
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50000}})
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", forwardedFor))
+			_, err := interceptor(ctx, nil, info, handler)
+			return err
+		}
+
+		if err := call("203.0.113.7"); err != nil {
+			t.Fatalf("Expected first client to be allowed, got: %v", err)
+		}
+		if err := call("198.51.100.1, 203.0.113.8"); err != nil {
+			t.Errorf("Expected second client to be allowed, got: %v", err)
+		}
+		if err := call("203.0.113.7"); status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("Expected first client to be limited, got: %v", err)
+		}
+	})
+}
+
+// countingAPIKeyStore counts the API key lookups made against it
+type countingAPIKeyStore struct {
+	fakeAPIKeyStore
+	lookups int
+}
+
+func (s *countingAPIKeyStore) GetActiveApiKeyByHash(ctx context.Context, keyHash string) (sqlc.ApiKey, error) {
+	s.lookups++
+	return s.fakeAPIKeyStore.GetActiveApiKeyByHash(ctx, keyHash)
+}
+
+func TestIPRateLimitInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: applicantsv1.ApplicantsService_GetApplicant_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	policy, err := ratelimit.NewPolicy(ratelimit.File{
+		Default: ratelimit.Limit{RequestsPerSecond: 100, Burst: 100},
+		PerIP:   &ratelimit.Limit{RequestsPerSecond: 1, Burst: 3},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create policy: %v", err)
+	}
+
+	t.Run("Bad API keys are throttled before they are looked up", func(t *testing.T) {
+		authenticator, err := auth.NewAuthenticator(auth.Config{HS256Secret: "test-secret-that-is-at-least-32-bytes"})
+		if err != nil {
+			t.Fatalf("failed to create authenticator: %v", err)
+		}
+		store := &countingAPIKeyStore{}
+		authInterceptor := AuthInterceptor(authenticator, auth.NewAPIKeyAuthenticator(store, zap.NewNop()), zap.NewNop())
+		ipInterceptor := IPRateLimitInterceptor(ratelimit.NewLimiter(policy), zap.NewNop())
+
+		call := func(addr net.IP, key string) error {
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: addr, Port: 50000}})
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", key))
+			_, err := ipInterceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return authInterceptor(ctx, req, info, handler)
+			})
+			return err
+		}
+
+		attacker := net.IPv4(203, 0, 113, 7)
+		for i := 0; i < 3; i++ {
+			if err := call(attacker, "ak_guess"); status.Code(err) != codes.Unauthenticated {
+				t.Fatalf("Expected guess %d to reach authentication, got %v", i+1, err)
+			}
+		}
+		for i := 0; i < 5; i++ {
+			if err := call(attacker, "ak_guess"); status.Code(err) != codes.ResourceExhausted {
+				t.Fatalf("Expected guesses over budget to be limited, got %v", err)
+			}
+		}
+		if store.lookups != 3 {
+			t.Errorf("Expected only the guesses within budget to be looked up, got %d lookups", store.lookups)
+		}
+
+		// Other addresses have their own budget
+		if err := call(net.IPv4(198, 51, 100, 1), "ak_guess"); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Expected another address to reach authentication, got %v", err)
+		}
+	})
+
+	t.Run("No per-IP limit", func(t *testing.T) {
+		unlimited, err := ratelimit.NewPolicy(ratelimit.File{Default: ratelimit.Limit{RequestsPerSecond: 1, Burst: 1}})
+		if err != nil {
+			t.Fatalf("Failed to create policy: %v", err)
+		}
+		interceptor := IPRateLimitInterceptor(ratelimit.NewLimiter(unlimited), zap.NewNop())
+
+		for i := 0; i < 5; i++ {
+			if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+				t.Fatalf("Expected every call to be allowed, got: %v", err)
+			}
+		}
+	})
+}
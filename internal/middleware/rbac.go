@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Thrun12/golang-assignment/internal/auth"
+	"github.com/Thrun12/golang-assignment/internal/rbac"
+)
+
+// permissionDeniedReason is the ErrorInfo reason reported for missing permissions
+const permissionDeniedReason = "MISSING_PERMISSION"
+
+// RBACInterceptor returns a gRPC unary server interceptor that rejects calls
+// whose caller lacks a permission the policy requires for the method. Roles come
+// from the authenticated principal. Without one they are read from the
+// rbac.RoleHeader metadata if trustRoleHeader is set, which is only safe behind
+// a proxy that authenticates callers and sets the header itself, and stored in
+// the context as an anonymous principal together with the rbac.AgencyHeader.
+func RBACInterceptor(policy *rbac.Policy, trustRoleHeader bool, logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		ctx, err := authorize(ctx, info.FullMethod, req, policy, trustRoleHeader, logger)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// RBACStreamInterceptor is the streaming counterpart of RBACInterceptor. The
+// permissions are checked once when the stream is opened, before any request
+// message is received. Server reflection stays public.
+func RBACStreamInterceptor(policy *rbac.Policy, trustRoleHeader bool, logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if isReflection(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx, err := authorize(ss.Context(), info.FullMethod, nil, policy, trustRoleHeader, logger)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authorize checks that the caller holds every permission the policy requires
+// for a call to method, and returns ctx with the caller's principal
+func authorize(ctx context.Context, method string, req interface{}, policy *rbac.Policy, trustRoleHeader bool, logger *zap.Logger) (context.Context, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok && trustRoleHeader {
+		principal = headerPrincipal(ctx)
+		ctx = auth.NewContext(ctx, principal)
+	}
+
+	var roles []string
+	if principal != nil {
+		roles = principal.Roles
+	}
+
+	required, known := policy.Required(method, req)
+	if !known {
+		// Only ApplicantsService methods are covered; anything else is denied
+		return nil, permissionDenied(method, "")
+	}
+
+	if missing, denied := policy.Missing(roles, required); denied {
+		logger.Debug("permission denied",
+			zap.String("method", method),
+			zap.Strings("roles", roles),
+			zap.String("missing_permission", missing),
+		)
+		return nil, permissionDenied(method, missing)
+	}
+
+	return ctx, nil
+}
+
+// headerPrincipal builds a principal from the role and agency header metadata
+func headerPrincipal(ctx context.Context) *auth.Principal {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	principal := &auth.Principal{}
+	for _, value := range md.Get(rbac.RoleHeader) {
+		for _, role := range strings.Split(value, ",") {
+			if role = strings.TrimSpace(role); role != "" {
+				principal.Roles = append(principal.Roles, role)
+			}
+		}
+	}
+	if agency := md.Get(rbac.AgencyHeader); len(agency) > 0 {
+		principal.Agency = strings.TrimSpace(agency[0])
+	}
+	return principal
+}
+
+// permissionDenied builds a PermissionDenied status naming the missing permission
+func permissionDenied(method, permission string) error {
+	msg := "permission denied for " + method
+	if permission != "" {
+		msg = "missing permission " + permission
+	}
+
+	st, err := status.New(codes.PermissionDenied, msg).WithDetails(&errdetails.ErrorInfo{
+		Reason: permissionDeniedReason,
+		Domain: "applicants.v1",
+		Metadata: map[string]string{
+			"method":     method,
+			"permission": permission,
+		},
+	})
+	if err != nil {
+		return status.Error(codes.PermissionDenied, msg)
+	}
+	return st.Err()
+}
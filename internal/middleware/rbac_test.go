@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/auth"
+	"github.com/Thrun12/golang-assignment/internal/rbac"
+)
+
+func TestRBACInterceptor(t *testing.T) {
+	deleteInfo := &grpc.UnaryServerInfo{FullMethod: applicantsv1.ApplicantsService_DeleteApplicant_FullMethodName}
+
+	tests := []struct {
+		name            string
+		principal       *auth.Principal
+		roleHeader      string
+		trustRoleHeader bool
+		expectedCode    codes.Code
+	}{
+		{name: "Admin principal", principal: &auth.Principal{Subject: "a", Roles: []string{rbac.RoleAdmin}}, expectedCode: codes.OK},
+		{name: "Recruiter principal", principal: &auth.Principal{Subject: "r", Roles: []string{rbac.RoleRecruiter}}, expectedCode: codes.PermissionDenied},
+		{name: "No principal", expectedCode: codes.PermissionDenied},
+		{name: "Trusted role header", roleHeader: "interviewer, admin", trustRoleHeader: true, expectedCode: codes.OK},
+		{name: "Untrusted role header", roleHeader: "admin", expectedCode: codes.PermissionDenied},
+		{
+			name:            "Principal takes precedence over role header",
+			principal:       &auth.Principal{Subject: "r", Roles: []string{rbac.RoleRecruiter}},
+			roleHeader:      "admin",
+			trustRoleHeader: true,
+			expectedCode:    codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := RBACInterceptor(rbac.DefaultPolicy(), tt.trustRoleHeader, zap.NewNop())
+
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = auth.NewContext(ctx, tt.principal)
+			}
+			if tt.roleHeader != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(rbac.RoleHeader, tt.roleHeader))
+			}
+
+			called := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				if _, ok := auth.FromContext(ctx); !ok {
+					t.Error("Expected principal in handler context")
+				}
+				return nil, nil
+			}
+
+			_, err := interceptor(ctx, &applicantsv1.DeleteApplicantRequest{Id: 1}, deleteInfo, handler)
+			if code := status.Code(err); code != tt.expectedCode {
+				t.Fatalf("Expected %s, got %v", tt.expectedCode, err)
+			}
+			if called != (tt.expectedCode == codes.OK) {
+				t.Errorf("Expected handler called=%v, got %v", tt.expectedCode == codes.OK, called)
+			}
+			if tt.expectedCode != codes.PermissionDenied {
+				return
+			}
+
+			var info *errdetails.ErrorInfo
+			for _, d := range status.Convert(err).Details() {
+				if ei, ok := d.(*errdetails.ErrorInfo); ok {
+					info = ei
+				}
+			}
+			if info == nil {
+				t.Fatal("Expected ErrorInfo detail")
+			}
+			if info.Metadata["permission"] != rbac.PermissionDeleteApplicants {
+				t.Errorf("Expected missing permission %s, got %s", rbac.PermissionDeleteApplicants, info.Metadata["permission"])
+			}
+		})
+	}
+}
@@ -20,15 +20,39 @@ func RecoveryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
 	) (resp interface{}, err error) {
 		defer func() {
 			if r := recover(); r != nil {
-				logger.Error("panic recovered",
-					zap.String("method", info.FullMethod),
-					zap.Any("panic", r),
-					zap.String("stack", string(debug.Stack())),
-				)
-				err = status.Errorf(codes.Internal, "internal server error: %v", r)
+				err = recovered(logger, info.FullMethod, r)
 			}
 		}()
 
 		return handler(ctx, req)
 	}
 }
+
+// RecoveryStreamInterceptor returns a gRPC stream server interceptor for panic recovery
+func RecoveryStreamInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(logger, info.FullMethod, r)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+// recovered logs a panic raised while handling method and returns the status
+// reported to the caller instead
+func recovered(logger *zap.Logger, method string, r interface{}) error {
+	logger.Error("panic recovered",
+		zap.String("method", method),
+		zap.Any("panic", r),
+		zap.String("stack", string(debug.Stack())),
+	)
+	return status.Errorf(codes.Internal, "internal server error: %v", r)
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/Thrun12/golang-assignment/internal/auth"
+	"github.com/Thrun12/golang-assignment/internal/redact"
+)
+
+// RedactionInterceptor returns a gRPC unary server interceptor that clears the
+// JobApplicant fields the caller may not see from every response, so the same
+// RPCs can serve recruiters, interviewers and external agencies. The policy is
+// also stored in the request context for handlers that filter on redacted fields.
+func RedactionInterceptor(policy *redact.Policy) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		ctx = redact.NewContext(ctx, policy)
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+
+		if msg, ok := resp.(proto.Message); ok {
+			principal, _ := auth.FromContext(ctx)
+			policy.Apply(principal, msg)
+		}
+		return resp, nil
+	}
+}
+
+// RedactionStreamInterceptor is the streaming counterpart of
+// RedactionInterceptor; every message sent on the stream is redacted
+func RedactionStreamInterceptor(policy *redact.Policy) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx := redact.NewContext(ss.Context(), policy)
+		return handler(srv, &redactingStream{ServerStream: &wrappedStream{ServerStream: ss, ctx: ctx}, policy: policy})
+	}
+}
+
+// redactingStream redacts the messages sent on a server stream
+type redactingStream struct {
+	grpc.ServerStream
+	policy *redact.Policy
+}
+
+// SendMsg redacts m for the caller before sending it
+func (s *redactingStream) SendMsg(m interface{}) error {
+	if msg, ok := m.(proto.Message); ok {
+		principal, _ := auth.FromContext(s.Context())
+		s.policy.Apply(principal, msg)
+	}
+	return s.ServerStream.SendMsg(m)
+}
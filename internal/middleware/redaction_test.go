@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/auth"
+	"github.com/Thrun12/golang-assignment/internal/redact"
+)
+
+func TestRedactionInterceptor(t *testing.T) {
+	interceptor := RedactionInterceptor(redact.DefaultPolicy())
+	info := &grpc.UnaryServerInfo{FullMethod: applicantsv1.ApplicantsService_GetApplicant_FullMethodName}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		if _, ok := redact.FromContext(ctx); !ok {
+			t.Error("Expected policy in handler context")
+		}
+		return &applicantsv1.GetApplicantResponse{
+			Applicant: &applicantsv1.JobApplicant{Id: 1, Email: "jane@example.com", SalaryExpectation: "100k"},
+		}, nil
+	}
+
+	ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "i", Roles: []string{"interviewer"}})
+	resp, err := interceptor(ctx, &applicantsv1.GetApplicantRequest{Id: 1}, info, handler)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	applicant := resp.(*applicantsv1.GetApplicantResponse).Applicant
+	if applicant.SalaryExpectation != "" {
+		t.Errorf("Expected salary to be redacted, got %q", applicant.SalaryExpectation)
+	}
+	if applicant.Email != "jane@example.com" {
+		t.Errorf("Expected email to be kept, got %q", applicant.Email)
+	}
+
+	// Errors pass through untouched
+	failing := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("boom")
+	}
+	if _, err := interceptor(ctx, nil, info, failing); err == nil {
+		t.Error("Expected handler error to be returned")
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+// wrappedStream is a server stream whose handler sees ctx instead of the
+// stream's own context, so stream interceptors can pass values down the chain
+// the way unary interceptors pass a new context
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the wrapped stream
+func (s *wrappedStream) Context() context.Context {
+	return s.ctx
+}
+
+// isReflection reports whether method belongs to the gRPC server reflection
+// service, which describes the API to tools like grpcurl and stays public like
+// the REST API docs
+func isReflection(method string) bool {
+	return strings.HasPrefix(method, "/grpc.reflection.")
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"buf.build/go/protovalidate"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/auth"
+	"github.com/Thrun12/golang-assignment/internal/logging/calllog"
+	"github.com/Thrun12/golang-assignment/internal/rbac"
+	"github.com/Thrun12/golang-assignment/internal/redact"
+	"github.com/Thrun12/golang-assignment/internal/requestid"
+)
+
+// fakeServerStream receives the queued requests and records what is sent
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+	recv   []proto.Message
+	sent   []interface{}
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func (s *fakeServerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *fakeServerStream) SendMsg(m interface{}) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	if len(s.recv) == 0 {
+		return io.EOF
+	}
+	proto.Merge(m.(proto.Message), s.recv[0])
+	s.recv = s.recv[1:]
+	return nil
+}
+
+var watchInfo = &grpc.StreamServerInfo{
+	FullMethod:     applicantsv1.ApplicantsService_WatchApplicants_FullMethodName,
+	IsServerStream: true,
+}
+
+func TestRecoveryStreamInterceptor(t *testing.T) {
+	interceptor := RecoveryStreamInterceptor(zap.NewNop())
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		panic("boom")
+	}
+
+	err := interceptor(nil, &fakeServerStream{ctx: context.Background()}, watchInfo, handler)
+	if status.Code(err) != codes.Internal {
+		t.Errorf("Expected Internal, got %v", err)
+	}
+}
+
+func TestStreamServerInterceptor_RequestID(t *testing.T) {
+	interceptor := StreamServerInterceptor(zap.NewNop(), calllog.DefaultPolicy())
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestid.Header, "req-abc-123"))
+	stream := &fakeServerStream{ctx: ctx}
+
+	var handled string
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		handled = requestid.FromContext(ss.Context())
+		return nil
+	}
+	if err := interceptor(nil, stream, watchInfo, handler); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if handled != "req-abc-123" {
+		t.Errorf("Expected request ID req-abc-123 in the handler context, got %q", handled)
+	}
+	if got := stream.header.Get(requestid.Header); len(got) != 1 || got[0] != "req-abc-123" {
+		t.Errorf("Expected request ID in response header, got %v", got)
+	}
+}
+
+func TestRBACStreamInterceptor(t *testing.T) {
+	interceptor := RBACStreamInterceptor(rbac.DefaultPolicy(), false, zap.NewNop())
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		return nil
+	}
+
+	tests := []struct {
+		name         string
+		principal    *auth.Principal
+		method       string
+		expectedCode codes.Code
+	}{
+		{name: "Reader", principal: &auth.Principal{Subject: "i", Roles: []string{rbac.RoleInterviewer}}, method: watchInfo.FullMethod, expectedCode: codes.OK},
+		{name: "No principal", method: watchInfo.FullMethod, expectedCode: codes.PermissionDenied},
+		{name: "Server reflection is public", method: "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo", expectedCode: codes.OK},
+		{name: "Unknown stream", principal: &auth.Principal{Subject: "a", Roles: []string{rbac.RoleAdmin}}, method: "/other.v1.Service/Watch", expectedCode: codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = auth.NewContext(ctx, tt.principal)
+			}
+
+			err := interceptor(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: tt.method}, handler)
+			if status.Code(err) != tt.expectedCode {
+				t.Errorf("Expected %v, got %v", tt.expectedCode, err)
+			}
+		})
+	}
+}
+
+func TestRedactionStreamInterceptor(t *testing.T) {
+	interceptor := RedactionStreamInterceptor(redact.DefaultPolicy())
+	ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "i", Roles: []string{rbac.RoleInterviewer}})
+	stream := &fakeServerStream{ctx: ctx}
+
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		return ss.SendMsg(&applicantsv1.WatchApplicantsResponse{
+			Applicant: &applicantsv1.JobApplicant{Id: 1, Email: "jane@example.com", SalaryExpectation: "100k"},
+		})
+	}
+	if err := interceptor(nil, stream, watchInfo, handler); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(stream.sent) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(stream.sent))
+	}
+	if salary := stream.sent[0].(*applicantsv1.WatchApplicantsResponse).Applicant.SalaryExpectation; salary != "" {
+		t.Errorf("Expected salary to be redacted, got %q", salary)
+	}
+}
+
+func TestValidationStreamInterceptor(t *testing.T) {
+	validator, err := protovalidate.New()
+	if err != nil {
+		t.Fatalf("failed to create validator: %v", err)
+	}
+	interceptor := ValidationStreamInterceptor(validator)
+
+	stream := &fakeServerStream{
+		ctx:  context.Background(),
+		recv: []proto.Message{&applicantsv1.CreateApplicantRequest{Name: "J", Email: "jane@example.com", Position: "Developer"}},
+	}
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		return ss.RecvMsg(&applicantsv1.CreateApplicantRequest{})
+	}
+
+	err = interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/applicants.v1.ApplicantsService/Test"}, handler)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"buf.build/go/protovalidate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ValidationInterceptor returns a gRPC unary server interceptor that enforces
+// the protovalidate rules declared on request messages in the proto files
+func ValidationInterceptor(validator protovalidate.Validator) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if msg, ok := req.(proto.Message); ok {
+			if err := validator.Validate(msg); err != nil {
+				return nil, validationStatus(err)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// ValidationStreamInterceptor is the streaming counterpart of
+// ValidationInterceptor; every request message received on the stream is
+// validated
+func ValidationStreamInterceptor(validator protovalidate.Validator) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		return handler(srv, &validatingStream{ServerStream: ss, validator: validator})
+	}
+}
+
+// validatingStream validates the messages received on a server stream
+type validatingStream struct {
+	grpc.ServerStream
+	validator protovalidate.Validator
+}
+
+// RecvMsg receives m and rejects it if it breaks its validation rules
+func (s *validatingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if msg, ok := m.(proto.Message); ok {
+		if err := s.validator.Validate(msg); err != nil {
+			return validationStatus(err)
+		}
+	}
+	return nil
+}
+
+// validationStatus converts a protovalidate error into an InvalidArgument status
+// carrying every violation as a BadRequest field violation
+func validationStatus(err error) error {
+	var verr *protovalidate.ValidationError
+	if !errors.As(err, &verr) {
+		// Compilation and runtime errors mean the rules themselves are broken
+		return status.Errorf(codes.Internal, "failed to validate request: %v", err)
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	descriptions := make([]string, 0, len(verr.Violations))
+	for _, v := range verr.Violations {
+		field := protovalidate.FieldPathString(v.Proto.GetField())
+		description := field + ": " + v.Proto.GetMessage()
+
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: description,
+		})
+		descriptions = append(descriptions, description)
+	}
+
+	msg := "validation failed: " + strings.Join(descriptions, "; ")
+	st, detailErr := status.New(codes.InvalidArgument, msg).WithDetails(badRequest)
+	if detailErr != nil {
+		return status.Error(codes.InvalidArgument, msg)
+	}
+	return st.Err()
+}
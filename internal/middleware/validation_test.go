@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"buf.build/go/protovalidate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+)
+
+func TestValidationInterceptor(t *testing.T) {
+	validator, err := protovalidate.New()
+	if err != nil {
+		t.Fatalf("failed to create validator: %v", err)
+	}
+	interceptor := ValidationInterceptor(validator)
+	info := &grpc.UnaryServerInfo{FullMethod: "/applicants.v1.ApplicantsService/Test"}
+
+	tests := []struct {
+		name           string
+		req            interface{}
+		expectCalled   bool
+		expectedFields []string
+	}{
+		{
+			name: "Valid create request",
+			req: &applicantsv1.CreateApplicantRequest{
+				Name:             "Jane Doe",
+				Email:            "jane@example.com",
+				Position:         "Developer",
+				YearsExperience:  5,
+				InterviewScore:   85.0,
+				CulturalFitScore: 90.0,
+				TechnicalScore:   88.0,
+			},
+			expectCalled: true,
+		},
+		{
+			name: "Invalid create request reports every field",
+			req: &applicantsv1.CreateApplicantRequest{
+				Name:           "J",
+				Email:          "not-an-email",
+				Position:       "Developer",
+				GithubStars:    -1,
+				InterviewScore: 150.0,
+			},
+			expectedFields: []string{"name", "email", "github_stars", "interview_score"},
+		},
+		{
+			name: "Email with display name",
+			req: &applicantsv1.CreateApplicantRequest{
+				Name:     "Jane Doe",
+				Email:    "Jane Doe <jane@example.com>",
+				Position: "Developer",
+			},
+			expectedFields: []string{"email"},
+		},
+		{
+			name: "Name too long",
+			req: &applicantsv1.CreateApplicantRequest{
+				Name:     strings.Repeat("a", 256),
+				Email:    "jane@example.com",
+				Position: "Developer",
+			},
+			expectedFields: []string{"name"},
+		},
+		{
+			name: "Partial update leaves unmasked fields empty",
+			req: &applicantsv1.UpdateApplicantRequest{
+				Id:         1,
+				FunFact:    "Aced the whiteboard round",
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"fun_fact"}},
+			},
+			expectCalled: true,
+		},
+		{
+			name:           "Update without id",
+			req:            &applicantsv1.UpdateApplicantRequest{Email: "invalid"},
+			expectedFields: []string{"id", "email"},
+		},
+		{
+			name:         "Request without rules",
+			req:          &applicantsv1.GetApplicantRequest{Id: 1},
+			expectCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return req, nil
+			}
+
+			_, err := interceptor(context.Background(), tt.req, info, handler)
+			if called != tt.expectCalled {
+				t.Errorf("Expected handler called=%v, got %v", tt.expectCalled, called)
+			}
+			if tt.expectCalled {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+
+			st, ok := status.FromError(err)
+			if !ok || st.Code() != codes.InvalidArgument {
+				t.Fatalf("Expected InvalidArgument status code, got %v", err)
+			}
+
+			fields := map[string]bool{}
+			for _, detail := range st.Details() {
+				if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+					for _, v := range badRequest.GetFieldViolations() {
+						fields[v.GetField()] = true
+					}
+				}
+			}
+			if len(fields) != len(tt.expectedFields) {
+				t.Errorf("Expected violations for %v, got %v", tt.expectedFields, fields)
+			}
+			for _, field := range tt.expectedFields {
+				if !fields[field] {
+					t.Errorf("Expected a violation for field '%s'", field)
+				}
+			}
+		})
+	}
+}
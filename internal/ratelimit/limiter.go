@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Metadata headers describing the caller's remaining budget, forwarded by the
+// REST gateway as X-RateLimit-* headers
+const (
+	LimitHeader     = "x-ratelimit-limit"
+	RemainingHeader = "x-ratelimit-remaining"
+	ResetHeader     = "x-ratelimit-reset"
+)
+
+// sweepInterval is how often buckets of idle clients are dropped
+const sweepInterval = time.Minute
+
+// Decision is the outcome of charging a call to a client's budget
+type Decision struct {
+	// Allowed reports whether the call may proceed
+	Allowed bool
+
+	// Limit is the size of the bucket the call was charged to
+	Limit int
+
+	// Remaining is the number of calls the client may still make right away
+	Remaining int
+
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+
+	// RetryAfter is the time until a rejected call would be allowed
+	RetryAfter time.Duration
+}
+
+// bucket is a client's token bucket for one limit
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Limiter tracks a token bucket per client and limit of a policy
+type Limiter struct {
+	policy *Policy
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewLimiter creates a Limiter enforcing policy
+func NewLimiter(policy *Policy) *Limiter {
+	return &Limiter{
+		policy:  policy,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow charges a call to method by client and reports whether it may proceed
+func (l *Limiter) Allow(client, method string) Decision {
+	limit, name := l.policy.Limit(method)
+	return l.take(client+"|"+name, limit)
+}
+
+// AllowIP charges a call from the client address ip to its per-IP budget and
+// reports whether it may proceed. Every call is allowed if the policy has no
+// per-IP limit.
+func (l *Limiter) AllowIP(ip string) Decision {
+	limit, ok := l.policy.PerIPLimit()
+	if !ok {
+		return Decision{Allowed: true}
+	}
+	return l.take("per_ip:"+ip, limit)
+}
+
+// take charges a call to the bucket key, created with limit if needed
+func (l *Limiter) take(key string, limit Limit) Decision {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), limit.Burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	allowed := b.limiter.AllowN(now, 1)
+	tokens := b.limiter.TokensAt(now)
+
+	d := Decision{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     refillTime(float64(limit.Burst)-tokens, limit.RequestsPerSecond),
+	}
+	if !allowed {
+		d.RetryAfter = refillTime(1-tokens, limit.RequestsPerSecond)
+	}
+	return d
+}
+
+// sweep drops the buckets of clients idle long enough for them to be full
+// again, which is indistinguishable from a new bucket. Must hold l.mu.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		limit := b.limiter.Limit()
+		full := time.Duration(float64(b.limiter.Burst()) / float64(limit) * float64(time.Second))
+		if now.Sub(b.lastSeen) > full {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// refillTime returns how long it takes to regain tokens at rps tokens per second
+func refillTime(tokens, rps float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	return time.Duration(tokens / rps * float64(time.Second))
+}
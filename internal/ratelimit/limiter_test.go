@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+)
+
+// newTestLimiter returns a limiter on a manual clock allowing 1 call per
+// second with bursts of 2, and 1 ListApplicants call per 10 seconds
+func newTestLimiter(t *testing.T) (*Limiter, *time.Time) {
+	t.Helper()
+	p, err := NewPolicy(File{
+		Default: Limit{RequestsPerSecond: 1, Burst: 2},
+		Methods: map[string]Limit{
+			applicantsv1.ApplicantsService_ListApplicants_FullMethodName: {RequestsPerSecond: 0.1, Burst: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create policy: %v", err)
+	}
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLimiter(p)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	get := applicantsv1.ApplicantsService_GetApplicant_FullMethodName
+	create := applicantsv1.ApplicantsService_CreateApplicant_FullMethodName
+	list := applicantsv1.ApplicantsService_ListApplicants_FullMethodName
+
+	t.Run("Burst then refill", func(t *testing.T) {
+		l, now := newTestLimiter(t)
+
+		first := l.Allow("alice", get)
+		if !first.Allowed || first.Limit != 2 || first.Remaining != 1 {
+			t.Errorf("Expected first call allowed with 1 remaining of 2, got %+v", first)
+		}
+		// Methods without their own limit share the default bucket
+		if d := l.Allow("alice", create); !d.Allowed || d.Remaining != 0 || d.Reset != 2*time.Second {
+			t.Errorf("Expected second call allowed with an empty bucket full again in 2s, got %+v", d)
+		}
+
+		denied := l.Allow("alice", get)
+		if denied.Allowed || denied.RetryAfter != time.Second {
+			t.Errorf("Expected third call denied with a retry in 1s, got %+v", denied)
+		}
+
+		*now = now.Add(time.Second)
+		if d := l.Allow("alice", get); !d.Allowed {
+			t.Errorf("Expected call allowed after refill, got %+v", d)
+		}
+	})
+
+	t.Run("Clients and method limits are separate", func(t *testing.T) {
+		l, _ := newTestLimiter(t)
+
+		if d := l.Allow("alice", list); !d.Allowed {
+			t.Fatalf("Expected first list allowed, got %+v", d)
+		}
+		if d := l.Allow("alice", list); d.Allowed || d.RetryAfter != 10*time.Second {
+			t.Errorf("Expected second list denied for 10s, got %+v", d)
+		}
+		if d := l.Allow("alice", get); !d.Allowed || d.Remaining != 1 {
+			t.Errorf("Expected default bucket untouched by list calls, got %+v", d)
+		}
+		if d := l.Allow("bob", list); !d.Allowed {
+			t.Errorf("Expected another client to have its own budget, got %+v", d)
+		}
+	})
+
+	t.Run("Idle buckets are dropped", func(t *testing.T) {
+		l, now := newTestLimiter(t)
+
+		l.Allow("alice", get)
+		l.Allow("bob", list)
+
+		*now = now.Add(5 * time.Minute)
+		l.Allow("carol", get)
+
+		if len(l.buckets) != 1 {
+			t.Errorf("Expected only the active client's bucket to remain, got %d", len(l.buckets))
+		}
+	})
+}
+
+func TestLimiter_AllowIP(t *testing.T) {
+	p, err := NewPolicy(File{
+		Default: Limit{RequestsPerSecond: 1, Burst: 1},
+		PerIP:   &Limit{RequestsPerSecond: 1, Burst: 2},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create policy: %v", err)
+	}
+	l := NewLimiter(p)
+
+	for i := 0; i < 2; i++ {
+		if d := l.AllowIP("203.0.113.7"); !d.Allowed {
+			t.Fatalf("Expected call %d allowed, got %+v", i+1, d)
+		}
+	}
+	if d := l.AllowIP("203.0.113.7"); d.Allowed || d.RetryAfter <= 0 {
+		t.Errorf("Expected third call denied, got %+v", d)
+	}
+	// The per-IP budget is separate from the budget of the caller it identifies
+	if d := l.Allow("ip:203.0.113.7", applicantsv1.ApplicantsService_GetApplicant_FullMethodName); !d.Allowed {
+		t.Errorf("Expected the caller's own budget untouched, got %+v", d)
+	}
+}
@@ -0,0 +1,152 @@
+package ratelimit
+
+import (
+	"fmt"
+	"os"
+
+	"go.yaml.in/yaml/v3"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+)
+
+// Limit is a token bucket: clients may make Burst calls at once and regain
+// RequestsPerSecond calls every second
+type Limit struct {
+	RequestsPerSecond float64 `yaml:"requests_per_second"`
+	Burst             int     `yaml:"burst"`
+}
+
+// File is the layout of a rate limit policy file (YAML or JSON)
+type File struct {
+	// Default is the budget every client shares across the methods without their own limit
+	Default Limit `yaml:"default"`
+
+	// Methods gives full gRPC method names a budget of their own per client
+	Methods map[string]Limit `yaml:"methods"`
+
+	// PerIP is the budget of every client address across all methods, charged
+	// before the caller is authenticated so that calls with missing or bad
+	// credentials are limited too. Without it callers are only charged once
+	// they are identified.
+	PerIP *Limit `yaml:"per_ip"`
+}
+
+// Policy decides which budget a call is charged to
+type Policy struct {
+	defaultLimit Limit
+	methods      map[string]Limit
+	perIP        *Limit
+}
+
+// DefaultPolicy returns the built-in policy: 20 calls per second with bursts
+// of 40 per client, with listing and searching capped lower since each call can
+// return 100 applicants, and score recomputation capped lowest. Every client
+// address may make 50 calls per second with bursts of 100 before authentication.
+func DefaultPolicy() *Policy {
+	p, err := NewPolicy(File{
+		Default: Limit{RequestsPerSecond: 20, Burst: 40},
+		PerIP:   &Limit{RequestsPerSecond: 50, Burst: 100},
+		Methods: map[string]Limit{
+			applicantsv1.ApplicantsService_ListApplicants_FullMethodName:   {RequestsPerSecond: 5, Burst: 10},
+			applicantsv1.ApplicantsService_SearchApplicants_FullMethodName: {RequestsPerSecond: 5, Burst: 10},
+			applicantsv1.ApplicantsService_RecomputeScores_FullMethodName:  {RequestsPerSecond: 0.1, Burst: 2},
+		},
+	})
+	if err != nil {
+		panic(fmt.Sprintf("invalid default rate limit policy: %v", err))
+	}
+	return p
+}
+
+// NewPolicy creates a policy from a parsed policy file
+func NewPolicy(file File) (*Policy, error) {
+	if err := file.Default.validate(); err != nil {
+		return nil, fmt.Errorf("default: %w", err)
+	}
+
+	known := make(map[string]bool)
+	for _, m := range applicantsv1.ApplicantsService_ServiceDesc.Methods {
+		known["/"+applicantsv1.ApplicantsService_ServiceDesc.ServiceName+"/"+m.MethodName] = true
+	}
+	for _, st := range applicantsv1.ApplicantsService_ServiceDesc.Streams {
+		known["/"+applicantsv1.ApplicantsService_ServiceDesc.ServiceName+"/"+st.StreamName] = true
+	}
+
+	if file.PerIP != nil {
+		if err := file.PerIP.validate(); err != nil {
+			return nil, fmt.Errorf("per_ip: %w", err)
+		}
+	}
+
+	p := &Policy{
+		defaultLimit: file.Default,
+		methods:      make(map[string]Limit, len(file.Methods)),
+		perIP:        file.PerIP,
+	}
+	for method, limit := range file.Methods {
+		if !known[method] {
+			return nil, fmt.Errorf("method %q: unknown method", method)
+		}
+		if err := limit.validate(); err != nil {
+			return nil, fmt.Errorf("method %q: %w", method, err)
+		}
+		p.methods[method] = limit
+	}
+	return p, nil
+}
+
+// validate checks that a limit admits at least one call
+func (l Limit) validate() error {
+	if l.RequestsPerSecond <= 0 {
+		return fmt.Errorf("requests_per_second must be positive")
+	}
+	if l.Burst < 1 {
+		return fmt.Errorf("burst must be at least 1")
+	}
+	return nil
+}
+
+// LoadPolicy loads a rate limit policy from a YAML or JSON file.
+// An empty path yields the default policy.
+func LoadPolicy(path string) (*Policy, error) {
+	if path == "" {
+		return DefaultPolicy(), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rate limit policy: %w", err)
+	}
+	defer f.Close()
+
+	var file File
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse rate limit policy %s: %w", path, err)
+	}
+
+	p, err := NewPolicy(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate limit policy %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Limit returns the budget charged for calls to method and the bucket it is
+// tracked in; methods without their own limit share the default bucket.
+func (p *Policy) Limit(method string) (Limit, string) {
+	if limit, ok := p.methods[method]; ok {
+		return limit, method
+	}
+	return p.defaultLimit, "*"
+}
+
+// PerIPLimit returns the budget client addresses are charged before
+// authentication, and false if the policy has none
+func (p *Policy) PerIPLimit() (Limit, bool) {
+	if p.perIP == nil {
+		return Limit{}, false
+	}
+	return *p.perIP, true
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+)
+
+func writePolicy(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write policy file: %v", err)
+	}
+	return path
+}
+
+func TestLoadPolicy(t *testing.T) {
+	t.Run("Shipped policy file", func(t *testing.T) {
+		p, err := LoadPolicy("../../configs/rate_limit_policy.yaml")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		list, bucket := p.Limit(applicantsv1.ApplicantsService_ListApplicants_FullMethodName)
+		if bucket != applicantsv1.ApplicantsService_ListApplicants_FullMethodName || list.Burst != 10 {
+			t.Errorf("Expected ListApplicants to have its own limit, got %+v in %q", list, bucket)
+		}
+
+		get, bucket := p.Limit(applicantsv1.ApplicantsService_GetApplicant_FullMethodName)
+		if bucket != "*" || get != DefaultPolicy().defaultLimit {
+			t.Errorf("Expected GetApplicant to use the default limit, got %+v in %q", get, bucket)
+		}
+
+		perIP, ok := p.PerIPLimit()
+		if want, _ := DefaultPolicy().PerIPLimit(); !ok || perIP != want {
+			t.Errorf("Expected the default per-IP limit, got %+v", perIP)
+		}
+	})
+
+	t.Run("Unknown method", func(t *testing.T) {
+		_, err := LoadPolicy(writePolicy(t, `
+default: {requests_per_second: 1, burst: 1}
+methods:
+  /applicants.v1.ApplicantsService/DeleteAllApplicants: {requests_per_second: 1, burst: 1}
+`))
+		if err == nil || !strings.Contains(err.Error(), "unknown method") {
+			t.Errorf("Expected unknown method error, got: %v", err)
+		}
+	})
+
+	t.Run("Limit without budget", func(t *testing.T) {
+		_, err := LoadPolicy(writePolicy(t, "default: {requests_per_second: 1, burst: 0}\n"))
+		if err == nil || !strings.Contains(err.Error(), "burst") {
+			t.Errorf("Expected burst error, got: %v", err)
+		}
+
+		_, err = LoadPolicy(writePolicy(t, "default: {burst: 5}\n"))
+		if err == nil || !strings.Contains(err.Error(), "requests_per_second") {
+			t.Errorf("Expected requests_per_second error, got: %v", err)
+		}
+	})
+
+	t.Run("Unknown key", func(t *testing.T) {
+		if _, err := LoadPolicy(writePolicy(t, "default: {rps: 1, burst: 1}\n")); err == nil {
+			t.Error("Expected error for unknown key")
+		}
+	})
+}
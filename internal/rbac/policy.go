@@ -0,0 +1,242 @@
+package rbac
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+)
+
+// Permissions required by ApplicantsService methods
+const (
+	PermissionReadApplicants    = "applicants.read"
+	PermissionCreateApplicants  = "applicants.create"
+	PermissionUpdateApplicants  = "applicants.update"
+	PermissionScoreApplicants   = "applicants.score"
+	PermissionTransitionStatus  = "applicants.transition"
+	PermissionDeleteApplicants  = "applicants.delete"
+	PermissionReadScoringModels = "scoring_models.read"
+	PermissionRecomputeScores   = "scores.recompute"
+	PermissionManageAPIKeys     = "api_keys.manage"
+	PermissionReadAuditLog      = "audit.read"
+	PermissionAll               = "*"
+)
+
+// Roles granted by the default policy
+const (
+	RoleInterviewer = "interviewer"
+	RoleRecruiter   = "recruiter"
+	RoleAdmin       = "admin"
+	RoleAgency      = "agency"
+)
+
+// Metadata headers carrying the caller's comma-separated roles and agency when
+// a trusted proxy authenticates callers in front of the service
+const (
+	RoleHeader   = "x-user-roles"
+	AgencyHeader = "x-user-agency"
+)
+
+// scoreFields are the update_mask paths an interviewer may change with
+// PermissionScoreApplicants alone
+var scoreFields = map[string]bool{
+	"interview_score":    true,
+	"cultural_fit_score": true,
+	"technical_score":    true,
+}
+
+// File is the layout of a policy file (YAML or JSON)
+type File struct {
+	// Roles maps each role to the permissions it grants; "*" grants every permission
+	Roles map[string][]string `yaml:"roles"`
+
+	// Methods maps each full gRPC method name to the permissions it requires
+	Methods map[string][]string `yaml:"methods"`
+}
+
+// Policy decides which permissions a method requires and which a role grants
+type Policy struct {
+	roles   map[string]map[string]bool
+	methods map[string][]string
+}
+
+// DefaultPolicy returns the built-in policy: interviewers read applicants and
+// add scores, recruiters also create, update and move applicants through the
+// workflow, and only admins delete applicants, recompute scores, manage API keys or read the
+// audit log. External agencies may read and submit applicants.
+func DefaultPolicy() *Policy {
+	p, err := NewPolicy(File{
+		Roles: map[string][]string{
+			RoleInterviewer: {PermissionReadApplicants, PermissionScoreApplicants, PermissionReadScoringModels},
+			RoleRecruiter: {
+				PermissionReadApplicants, PermissionScoreApplicants, PermissionReadScoringModels,
+				PermissionCreateApplicants, PermissionUpdateApplicants, PermissionTransitionStatus,
+			},
+			RoleAdmin:  {PermissionAll},
+			RoleAgency: {PermissionReadApplicants, PermissionCreateApplicants},
+		},
+		Methods: map[string][]string{
+			applicantsv1.ApplicantsService_ListApplicants_FullMethodName:               {PermissionReadApplicants},
+			applicantsv1.ApplicantsService_SearchApplicants_FullMethodName:             {PermissionReadApplicants},
+			applicantsv1.ApplicantsService_GetApplicant_FullMethodName:                 {PermissionReadApplicants},
+			applicantsv1.ApplicantsService_GetApplicantScoreExplanation_FullMethodName: {PermissionReadApplicants},
+			applicantsv1.ApplicantsService_GetBestApplicant_FullMethodName:             {PermissionReadApplicants},
+			applicantsv1.ApplicantsService_ListStatusHistory_FullMethodName:            {PermissionReadApplicants},
+			applicantsv1.ApplicantsService_WatchApplicants_FullMethodName:              {PermissionReadApplicants},
+			applicantsv1.ApplicantsService_CreateApplicant_FullMethodName:              {PermissionCreateApplicants},
+			applicantsv1.ApplicantsService_UpdateApplicant_FullMethodName:              {PermissionUpdateApplicants},
+			applicantsv1.ApplicantsService_TransitionApplicantStatus_FullMethodName:    {PermissionTransitionStatus},
+			applicantsv1.ApplicantsService_DeleteApplicant_FullMethodName:              {PermissionDeleteApplicants},
+			applicantsv1.ApplicantsService_RestoreApplicant_FullMethodName:             {PermissionDeleteApplicants},
+			applicantsv1.ApplicantsService_PurgeApplicant_FullMethodName:               {PermissionDeleteApplicants},
+			applicantsv1.ApplicantsService_ListScoringModels_FullMethodName:            {PermissionReadScoringModels},
+			applicantsv1.ApplicantsService_GetScoringModel_FullMethodName:              {PermissionReadScoringModels},
+			applicantsv1.ApplicantsService_RecomputeScores_FullMethodName:              {PermissionRecomputeScores},
+			applicantsv1.ApplicantsService_CreateApiKey_FullMethodName:                 {PermissionManageAPIKeys},
+			applicantsv1.ApplicantsService_RevokeApiKey_FullMethodName:                 {PermissionManageAPIKeys},
+			applicantsv1.ApplicantsService_ListApiKeys_FullMethodName:                  {PermissionManageAPIKeys},
+			applicantsv1.ApplicantsService_ListAuditEvents_FullMethodName:              {PermissionReadAuditLog},
+		},
+	})
+	if err != nil {
+		panic(fmt.Sprintf("invalid default policy: %v", err))
+	}
+	return p
+}
+
+// NewPolicy creates a policy from a parsed policy file. Every ApplicantsService
+// method must be listed so new RPCs are never exposed by accident.
+func NewPolicy(file File) (*Policy, error) {
+	p := &Policy{
+		roles:   make(map[string]map[string]bool, len(file.Roles)),
+		methods: make(map[string][]string, len(file.Methods)),
+	}
+
+	for role, permissions := range file.Roles {
+		role = strings.TrimSpace(role)
+		if role == "" {
+			return nil, fmt.Errorf("role name is required")
+		}
+		granted := make(map[string]bool, len(permissions))
+		for _, permission := range permissions {
+			if strings.TrimSpace(permission) == "" {
+				return nil, fmt.Errorf("role %q: empty permission", role)
+			}
+			granted[permission] = true
+		}
+		p.roles[role] = granted
+	}
+
+	known := make(map[string]bool)
+	for _, m := range applicantsv1.ApplicantsService_ServiceDesc.Methods {
+		known["/"+applicantsv1.ApplicantsService_ServiceDesc.ServiceName+"/"+m.MethodName] = true
+	}
+	for _, st := range applicantsv1.ApplicantsService_ServiceDesc.Streams {
+		known["/"+applicantsv1.ApplicantsService_ServiceDesc.ServiceName+"/"+st.StreamName] = true
+	}
+
+	for method, permissions := range file.Methods {
+		if !known[method] {
+			return nil, fmt.Errorf("method %q: unknown method", method)
+		}
+		if len(permissions) == 0 {
+			return nil, fmt.Errorf("method %q: at least one permission is required", method)
+		}
+		p.methods[method] = permissions
+	}
+
+	var missing []string
+	for method := range known {
+		if _, ok := p.methods[method]; !ok {
+			missing = append(missing, method)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("no permissions configured for %s", strings.Join(missing, ", "))
+	}
+
+	return p, nil
+}
+
+// LoadPolicy loads a policy from a YAML or JSON file.
+// An empty path yields the default policy.
+func LoadPolicy(path string) (*Policy, error) {
+	if path == "" {
+		return DefaultPolicy(), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open access policy: %w", err)
+	}
+	defer f.Close()
+
+	var file File
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse access policy %s: %w", path, err)
+	}
+
+	p, err := NewPolicy(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid access policy %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Required returns the permissions needed to call method with req, and false
+// if the method is not covered by the policy. A partial update that only
+// changes scores needs PermissionScoreApplicants instead of the method's
+// permissions, so interviewers can record their scores.
+func (p *Policy) Required(method string, req interface{}) ([]string, bool) {
+	permissions, ok := p.methods[method]
+	if !ok {
+		return nil, false
+	}
+
+	if update, isUpdate := req.(*applicantsv1.UpdateApplicantRequest); isUpdate && scoresOnly(update) {
+		return []string{PermissionScoreApplicants}, true
+	}
+	return permissions, true
+}
+
+// Granted reports whether any of roles grants permission
+func (p *Policy) Granted(roles []string, permission string) bool {
+	for _, role := range roles {
+		granted := p.roles[role]
+		if granted[permission] || granted[PermissionAll] {
+			return true
+		}
+	}
+	return false
+}
+
+// Missing returns the first permission in required that roles do not grant
+func (p *Policy) Missing(roles []string, required []string) (string, bool) {
+	for _, permission := range required {
+		if !p.Granted(roles, permission) {
+			return permission, true
+		}
+	}
+	return "", false
+}
+
+// scoresOnly reports whether an update's mask only touches score fields
+func scoresOnly(req *applicantsv1.UpdateApplicantRequest) bool {
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		return false
+	}
+	for _, path := range paths {
+		if !scoreFields[path] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+package rbac
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+)
+
+func writePolicy(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write policy file: %v", err)
+	}
+	return path
+}
+
+// allowed reports whether roles may call method with req under p
+func allowed(p *Policy, roles []string, method string, req interface{}) bool {
+	required, ok := p.Required(method, req)
+	if !ok {
+		return false
+	}
+	_, denied := p.Missing(roles, required)
+	return !denied
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	scoreUpdate := &applicantsv1.UpdateApplicantRequest{
+		Id:             1,
+		InterviewScore: 90,
+		UpdateMask:     &fieldmaskpb.FieldMask{Paths: []string{"interview_score"}},
+	}
+	nameUpdate := &applicantsv1.UpdateApplicantRequest{
+		Id:         1,
+		Name:       "Jane Doe",
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name", "interview_score"}},
+	}
+
+	tests := []struct {
+		name    string
+		roles   []string
+		method  string
+		req     interface{}
+		allowed bool
+	}{
+		{"Interviewer reads applicants", []string{RoleInterviewer}, applicantsv1.ApplicantsService_ListApplicants_FullMethodName, nil, true},
+		{"Interviewer adds scores", []string{RoleInterviewer}, applicantsv1.ApplicantsService_UpdateApplicant_FullMethodName, scoreUpdate, true},
+		{"Interviewer cannot update other fields", []string{RoleInterviewer}, applicantsv1.ApplicantsService_UpdateApplicant_FullMethodName, nameUpdate, false},
+		{"Interviewer cannot create", []string{RoleInterviewer}, applicantsv1.ApplicantsService_CreateApplicant_FullMethodName, nil, false},
+		{"Recruiter creates", []string{RoleRecruiter}, applicantsv1.ApplicantsService_CreateApplicant_FullMethodName, nil, true},
+		{"Recruiter updates", []string{RoleRecruiter}, applicantsv1.ApplicantsService_UpdateApplicant_FullMethodName, nameUpdate, true},
+		{"Recruiter cannot delete", []string{RoleRecruiter}, applicantsv1.ApplicantsService_DeleteApplicant_FullMethodName, nil, false},
+		{"Recruiter cannot recompute scores", []string{RoleRecruiter}, applicantsv1.ApplicantsService_RecomputeScores_FullMethodName, nil, false},
+		{"Admin deletes", []string{RoleAdmin}, applicantsv1.ApplicantsService_DeleteApplicant_FullMethodName, nil, true},
+		{"Recruiter cannot manage API keys", []string{RoleRecruiter}, applicantsv1.ApplicantsService_CreateApiKey_FullMethodName, nil, false},
+		{"Admin purges", []string{RoleAdmin}, applicantsv1.ApplicantsService_PurgeApplicant_FullMethodName, nil, true},
+		{"Roles combine", []string{"unknown", RoleRecruiter}, applicantsv1.ApplicantsService_CreateApplicant_FullMethodName, nil, true},
+		{"No roles", nil, applicantsv1.ApplicantsService_ListApplicants_FullMethodName, nil, false},
+		{"Unknown method", []string{RoleAdmin}, "/applicants.v1.ApplicantsService/DeleteAllApplicants", nil, false},
+	}
+
+	p := DefaultPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allowed(p, tt.roles, tt.method, tt.req); got != tt.allowed {
+				t.Errorf("Expected allowed=%v, got %v", tt.allowed, got)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	t.Run("Empty path uses default policy", func(t *testing.T) {
+		p, err := LoadPolicy("")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if !allowed(p, []string{RoleAdmin}, applicantsv1.ApplicantsService_DeleteApplicant_FullMethodName, nil) {
+			t.Error("Expected admin to be allowed to delete")
+		}
+	})
+
+	t.Run("Shipped policy file", func(t *testing.T) {
+		p, err := LoadPolicy("../../configs/rbac_policy.yaml")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if allowed(p, []string{RoleRecruiter}, applicantsv1.ApplicantsService_DeleteApplicant_FullMethodName, nil) {
+			t.Error("Expected recruiter to be denied delete")
+		}
+		if !allowed(p, []string{RoleInterviewer}, applicantsv1.ApplicantsService_GetApplicant_FullMethodName, nil) {
+			t.Error("Expected interviewer to be allowed to read")
+		}
+	})
+
+	t.Run("Unknown method", func(t *testing.T) {
+		_, err := LoadPolicy(writePolicy(t, `
+methods:
+  /applicants.v1.ApplicantsService/DeleteAllApplicants: [applicants.delete]
+`))
+		if err == nil || !strings.Contains(err.Error(), "unknown method") {
+			t.Errorf("Expected unknown method error, got: %v", err)
+		}
+	})
+
+	t.Run("Missing methods", func(t *testing.T) {
+		_, err := LoadPolicy(writePolicy(t, `
+roles:
+  admin: ["*"]
+methods:
+  /applicants.v1.ApplicantsService/ListApplicants: [applicants.read]
+`))
+		if err == nil || !strings.Contains(err.Error(), "RecomputeScores") {
+			t.Errorf("Expected error listing unconfigured methods, got: %v", err)
+		}
+	})
+
+	t.Run("Unknown key", func(t *testing.T) {
+		if _, err := LoadPolicy(writePolicy(t, "groups: {}\n")); err == nil {
+			t.Error("Expected error for unknown key")
+		}
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		if _, err := LoadPolicy(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+			t.Error("Expected error for missing file")
+		}
+	})
+}
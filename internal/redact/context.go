@@ -0,0 +1,17 @@
+package redact
+
+import "context"
+
+// policyKey is the context key for the field policy applied to a request
+type policyKey struct{}
+
+// NewContext returns a copy of ctx carrying policy
+func NewContext(ctx context.Context, policy *Policy) context.Context {
+	return context.WithValue(ctx, policyKey{}, policy)
+}
+
+// FromContext returns the field policy stored in ctx, if any
+func FromContext(ctx context.Context) (*Policy, bool) {
+	policy, ok := ctx.Value(policyKey{}).(*Policy)
+	return policy, ok && policy != nil
+}
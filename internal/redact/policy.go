@@ -0,0 +1,225 @@
+package redact
+
+import (
+	"fmt"
+	"os"
+
+	"go.yaml.in/yaml/v3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/auth"
+)
+
+// applicantDescriptor describes the JobApplicant message the policy applies to
+var applicantDescriptor = (&applicantsv1.JobApplicant{}).ProtoReflect().Descriptor()
+
+// searchResultDescriptor describes SearchResult, whose snippet quotes applicant fields
+var searchResultDescriptor = (&applicantsv1.SearchResult{}).ProtoReflect().Descriptor()
+
+// snippetFields are the JobApplicant fields SearchResult.snippet is built from
+// by the SearchApplicants query. The snippet is cleared when any of them is
+// redacted from the result's applicant.
+var snippetFields = map[protoreflect.Name]bool{
+	"name":     true,
+	"position": true,
+	"skills":   true,
+	"fun_fact": true,
+}
+
+// Rule restricts who may see a JobApplicant field
+type Rule struct {
+	// VisibleTo lists the roles that see the field
+	VisibleTo []string `yaml:"visible_to"`
+
+	// OwnAgency also shows the field to callers whose agency submitted the applicant
+	OwnAgency bool `yaml:"own_agency"`
+}
+
+// File is the layout of a field policy file (YAML or JSON)
+type File struct {
+	// Fields maps JobApplicant field names to visibility rules; unlisted fields
+	// are visible to every caller
+	Fields map[string]Rule `yaml:"fields"`
+}
+
+// field is a rule bound to the field it guards
+type field struct {
+	desc      protoreflect.FieldDescriptor
+	visibleTo map[string]bool
+	ownAgency bool
+}
+
+// Policy clears JobApplicant fields the caller may not see
+type Policy struct {
+	fields []field
+}
+
+// DefaultPolicy returns the built-in policy: only recruiters and admins see
+// salary expectations, and agencies only see the emails of their own applicants.
+func DefaultPolicy() *Policy {
+	p, err := NewPolicy(File{
+		Fields: map[string]Rule{
+			"salary_expectation": {VisibleTo: []string{"recruiter", "admin"}},
+			"email":              {VisibleTo: []string{"interviewer", "recruiter", "admin"}, OwnAgency: true},
+		},
+	})
+	if err != nil {
+		panic(fmt.Sprintf("invalid default field policy: %v", err))
+	}
+	return p
+}
+
+// NewPolicy creates a policy from a parsed policy file
+func NewPolicy(file File) (*Policy, error) {
+	p := &Policy{}
+	for name, rule := range file.Fields {
+		desc := applicantDescriptor.Fields().ByName(protoreflect.Name(name))
+		if desc == nil {
+			return nil, fmt.Errorf("field %q: unknown JobApplicant field", name)
+		}
+		if name == "id" || name == "etag" {
+			return nil, fmt.Errorf("field %q: cannot be redacted", name)
+		}
+
+		f := field{desc: desc, visibleTo: make(map[string]bool), ownAgency: rule.OwnAgency}
+		for _, role := range rule.VisibleTo {
+			f.visibleTo[role] = true
+		}
+		p.fields = append(p.fields, f)
+	}
+	return p, nil
+}
+
+// LoadPolicy loads a field policy from a YAML or JSON file.
+// An empty path yields the default policy.
+func LoadPolicy(path string) (*Policy, error) {
+	if path == "" {
+		return DefaultPolicy(), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open field policy: %w", err)
+	}
+	defer f.Close()
+
+	var file File
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse field policy %s: %w", path, err)
+	}
+
+	p, err := NewPolicy(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid field policy %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Apply clears the fields principal may not see in every JobApplicant within
+// msg, including applicants nested in lists. A nil principal sees only the
+// fields without a rule.
+func (p *Policy) Apply(principal *auth.Principal, msg proto.Message) {
+	if msg == nil || len(p.fields) == 0 {
+		return
+	}
+	p.walk(principal, msg.ProtoReflect())
+}
+
+// Visibility reports whose applicants principal may see the named field of:
+// every applicant when all is true, otherwise only those submitted by agency
+// (none when agency is empty). Queries matching on a field use it to avoid
+// revealing values the response would redact.
+func (p *Policy) Visibility(principal *auth.Principal, name string) (all bool, agency string) {
+	for _, f := range p.fields {
+		if string(f.desc.Name()) != name {
+			continue
+		}
+		if principal == nil {
+			return false, ""
+		}
+		for _, role := range principal.Roles {
+			if f.visibleTo[role] {
+				return true, ""
+			}
+		}
+		if f.ownAgency {
+			return false, principal.Agency
+		}
+		return false, ""
+	}
+	return true, ""
+}
+
+// walk redacts m if it is an applicant or search result, or descends into its
+// message fields
+func (p *Policy) walk(principal *auth.Principal, m protoreflect.Message) {
+	switch m.Descriptor().FullName() {
+	case applicantDescriptor.FullName():
+		p.redact(principal, m)
+		return
+	case searchResultDescriptor.FullName():
+		p.redactSearchResult(principal, m)
+		return
+	}
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Message() == nil || fd.IsMap() {
+			return true
+		}
+		if fd.IsList() {
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				p.walk(principal, list.Get(i).Message())
+			}
+			return true
+		}
+		p.walk(principal, v.Message())
+		return true
+	})
+}
+
+// redact clears the fields of applicant that principal may not see and
+// reports whether any of them is quoted in search snippets
+func (p *Policy) redact(principal *auth.Principal, applicant protoreflect.Message) bool {
+	quoted := false
+	for _, f := range p.fields {
+		if !f.visible(principal, applicant) && applicant.Has(f.desc) {
+			applicant.Clear(f.desc)
+			quoted = quoted || snippetFields[f.desc.Name()]
+		}
+	}
+	return quoted
+}
+
+// redactSearchResult redacts the applicant of result, and clears the snippet
+// if it may quote a redacted field
+func (p *Policy) redactSearchResult(principal *auth.Principal, result protoreflect.Message) {
+	applicantField := searchResultDescriptor.Fields().ByName("applicant")
+	if !result.Has(applicantField) {
+		return
+	}
+	if p.redact(principal, result.Mutable(applicantField).Message()) {
+		result.Clear(searchResultDescriptor.Fields().ByName("snippet"))
+	}
+}
+
+// visible reports whether principal may see the field on applicant
+func (f field) visible(principal *auth.Principal, applicant protoreflect.Message) bool {
+	if principal == nil {
+		return false
+	}
+	for _, role := range principal.Roles {
+		if f.visibleTo[role] {
+			return true
+		}
+	}
+	if f.ownAgency && principal.Agency != "" {
+		agency := applicant.Get(applicantDescriptor.Fields().ByName("agency")).String()
+		return agency == principal.Agency
+	}
+	return false
+}
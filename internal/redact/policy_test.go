@@ -0,0 +1,203 @@
+package redact
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/auth"
+)
+
+func newApplicant(agency string) *applicantsv1.JobApplicant {
+	return &applicantsv1.JobApplicant{
+		Id:                1,
+		Name:              "Jane Doe",
+		Email:             "jane@example.com",
+		SalaryExpectation: "100k",
+		OverallScore:      88.5,
+		Agency:            agency,
+	}
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		principal    *auth.Principal
+		agency       string
+		expectEmail  bool
+		expectSalary bool
+	}{
+		{name: "Recruiter sees everything", principal: &auth.Principal{Roles: []string{"recruiter"}}, expectEmail: true, expectSalary: true},
+		{name: "Interviewer does not see salary", principal: &auth.Principal{Roles: []string{"interviewer"}}, expectEmail: true},
+		{name: "Most permissive role wins", principal: &auth.Principal{Roles: []string{"interviewer", "admin"}}, expectEmail: true, expectSalary: true},
+		{name: "Agency sees own applicant's email", principal: &auth.Principal{Roles: []string{"agency"}, Agency: "talent-co"}, agency: "talent-co", expectEmail: true},
+		{name: "Agency does not see other agency's email", principal: &auth.Principal{Roles: []string{"agency"}, Agency: "talent-co"}, agency: "hire-fast"},
+		{name: "Agency does not see direct applicant's email", principal: &auth.Principal{Roles: []string{"agency"}, Agency: "talent-co"}},
+		{name: "No principal", agency: "talent-co"},
+	}
+
+	p := DefaultPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applicant := newApplicant(tt.agency)
+			p.Apply(tt.principal, applicant)
+
+			if got := applicant.Email != ""; got != tt.expectEmail {
+				t.Errorf("Expected email visible=%v, got %q", tt.expectEmail, applicant.Email)
+			}
+			if got := applicant.SalaryExpectation != ""; got != tt.expectSalary {
+				t.Errorf("Expected salary visible=%v, got %q", tt.expectSalary, applicant.SalaryExpectation)
+			}
+			if applicant.Name != "Jane Doe" || applicant.OverallScore != 88.5 {
+				t.Errorf("Expected unlisted fields to be kept, got %+v", applicant)
+			}
+		})
+	}
+}
+
+func TestVisibility(t *testing.T) {
+	tests := []struct {
+		name       string
+		principal  *auth.Principal
+		field      string
+		wantAll    bool
+		wantAgency string
+	}{
+		{name: "Role sees every applicant", principal: &auth.Principal{Roles: []string{"interviewer"}}, field: "email", wantAll: true},
+		{name: "Agency sees own applicants", principal: &auth.Principal{Roles: []string{"agency"}, Agency: "talent-co"}, field: "email", wantAgency: "talent-co"},
+		{name: "Field without own agency rule", principal: &auth.Principal{Roles: []string{"agency"}, Agency: "talent-co"}, field: "salary_expectation"},
+		{name: "No principal", field: "email"},
+		{name: "Unlisted field", field: "name", wantAll: true},
+	}
+
+	p := DefaultPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			all, agency := p.Visibility(tt.principal, tt.field)
+			if all != tt.wantAll || agency != tt.wantAgency {
+				t.Errorf("Expected (%v, %q), got (%v, %q)", tt.wantAll, tt.wantAgency, all, agency)
+			}
+		})
+	}
+}
+
+func TestApply_NestedApplicants(t *testing.T) {
+	resp := &applicantsv1.ListApplicantsResponse{
+		Applicants: []*applicantsv1.JobApplicant{newApplicant(""), newApplicant("")},
+	}
+
+	DefaultPolicy().Apply(&auth.Principal{Roles: []string{"interviewer"}}, resp)
+
+	for i, a := range resp.Applicants {
+		if a.SalaryExpectation != "" {
+			t.Errorf("Expected salary of applicant %d to be redacted", i)
+		}
+		if a.Email == "" {
+			t.Errorf("Expected email of applicant %d to be kept", i)
+		}
+	}
+}
+
+func TestApply_SearchResults(t *testing.T) {
+	agency := &auth.Principal{Roles: []string{"agency"}, Agency: "talent-co"}
+
+	t.Run("Snippet kept when only unquoted fields are redacted", func(t *testing.T) {
+		resp := &applicantsv1.SearchApplicantsResponse{
+			Results: []*applicantsv1.SearchResult{
+				{Applicant: newApplicant("hire-fast"), Snippet: "<mark>Jane</mark> Doe"},
+			},
+		}
+
+		DefaultPolicy().Apply(agency, resp)
+
+		result := resp.Results[0]
+		if result.Applicant.Email != "" {
+			t.Errorf("Expected other agency's email to be redacted, got %q", result.Applicant.Email)
+		}
+		if result.Snippet != "<mark>Jane</mark> Doe" {
+			t.Errorf("Expected snippet to be kept, got %q", result.Snippet)
+		}
+	})
+
+	t.Run("Snippet cleared when a quoted field is redacted", func(t *testing.T) {
+		p, err := NewPolicy(File{Fields: map[string]Rule{
+			"fun_fact": {VisibleTo: []string{"recruiter"}, OwnAgency: true},
+		}})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		own := newApplicant("talent-co")
+		own.FunFact = "Juggles"
+		other := newApplicant("hire-fast")
+		other.FunFact = "Juggles"
+		resp := &applicantsv1.SearchApplicantsResponse{
+			Results: []*applicantsv1.SearchResult{
+				{Applicant: own, Snippet: "<mark>Juggles</mark>"},
+				{Applicant: other, Snippet: "<mark>Juggles</mark>"},
+			},
+		}
+
+		p.Apply(agency, resp)
+
+		if resp.Results[0].Snippet == "" {
+			t.Error("Expected snippet of the agency's own applicant to be kept")
+		}
+		if resp.Results[1].Applicant.FunFact != "" || resp.Results[1].Snippet != "" {
+			t.Errorf("Expected fun fact and snippet of other agency's applicant to be redacted, got %q and %q",
+				resp.Results[1].Applicant.FunFact, resp.Results[1].Snippet)
+		}
+	})
+}
+
+func TestLoadPolicy(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "fields.yaml")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write policy file: %v", err)
+		}
+		return path
+	}
+
+	t.Run("Shipped policy file", func(t *testing.T) {
+		p, err := LoadPolicy("../../configs/redaction_policy.yaml")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		applicant := newApplicant("")
+		p.Apply(&auth.Principal{Roles: []string{"interviewer"}}, applicant)
+		if applicant.SalaryExpectation != "" {
+			t.Error("Expected salary to be redacted for interviewers")
+		}
+	})
+
+	t.Run("Scores hidden from agencies", func(t *testing.T) {
+		p, err := LoadPolicy(write(t, `
+fields:
+  overall_score:
+    visible_to: [recruiter]
+`))
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		applicant := newApplicant("")
+		p.Apply(&auth.Principal{Roles: []string{"agency"}}, applicant)
+		if applicant.OverallScore != 0 {
+			t.Errorf("Expected overall score to be redacted, got %.2f", applicant.OverallScore)
+		}
+	})
+
+	t.Run("Unknown field", func(t *testing.T) {
+		if _, err := LoadPolicy(write(t, "fields:\n  salary: {visible_to: [admin]}\n")); err == nil {
+			t.Error("Expected error for unknown field")
+		}
+	})
+
+	t.Run("Identifier cannot be redacted", func(t *testing.T) {
+		if _, err := LoadPolicy(write(t, "fields:\n  id: {visible_to: [admin]}\n")); err == nil {
+			t.Error("Expected error for redacting id")
+		}
+	})
+}
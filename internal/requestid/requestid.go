@@ -0,0 +1,46 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Header is the HTTP header and gRPC metadata key carrying the request ID
+const Header = "x-request-id"
+
+// maxLength bounds client-supplied request IDs so they stay cheap to log
+const maxLength = 128
+
+// idKey is the context key for the request ID
+type idKey struct{}
+
+// New generates a request ID
+func New() string {
+	return uuid.New().String()
+}
+
+// Valid reports whether a client-supplied request ID can be used as is: up to
+// 128 printable ASCII characters without spaces
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext returns a copy of ctx carrying the request ID
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+// FromContext returns the request ID stored in ctx, or "" if there is none
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(idKey{}).(string)
+	return id
+}
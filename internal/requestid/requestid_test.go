@@ -0,0 +1,40 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{name: "UUID", id: New(), want: true},
+		{name: "Opaque token", id: "req_01HZX3:trace.42", want: true},
+		{name: "Empty", id: "", want: false},
+		{name: "Too long", id: strings.Repeat("a", 129), want: false},
+		{name: "Whitespace", id: "abc def", want: false},
+		{name: "Control characters", id: "abc\ndef", want: false},
+		{name: "Non-ASCII", id: "req-é", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Valid(tt.id); got != tt.want {
+				t.Errorf("Valid(%q) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContext(t *testing.T) {
+	if id := FromContext(context.Background()); id != "" {
+		t.Errorf("Expected no request ID, got %q", id)
+	}
+	if id := FromContext(NewContext(context.Background(), "req-1")); id != "req-1" {
+		t.Errorf("Expected req-1, got %q", id)
+	}
+}
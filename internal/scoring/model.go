@@ -0,0 +1,261 @@
+package scoring
+
+import (
+	"math"
+	"strings"
+)
+
+// Input holds the applicant attributes a scoring model looks at
+type Input struct {
+	Name               string
+	Position           string
+	Skills             []string
+	YearsExperience    int32
+	InterviewScore     float64
+	CulturalFitScore   float64
+	TechnicalScore     float64
+	CanExitVim         bool
+	KnowsGo            bool
+	DebugsInProduction bool
+}
+
+// Weights are the weights of the three assessment scores in the base score
+type Weights struct {
+	Technical   float64 `yaml:"technical"`
+	Interview   float64 `yaml:"interview"`
+	CulturalFit float64 `yaml:"cultural_fit"`
+}
+
+// Condition restricts when a rule applies. Unset fields are ignored and all
+// set fields must match.
+type Condition struct {
+	HasSkills          []string `yaml:"has_skills"`
+	LacksSkills        []string `yaml:"lacks_skills"`
+	KnowsGo            *bool    `yaml:"knows_go"`
+	CanExitVim         *bool    `yaml:"can_exit_vim"`
+	DebugsInProduction *bool    `yaml:"debugs_in_production"`
+	MinYearsExperience int32    `yaml:"min_years_experience"`
+}
+
+// Rule is a bonus or penalty applied, in order, after the weighted base score.
+// The additive delta is Add + YearsExperience*PerYearExperience +
+// (len(Skills)-PerSkillAbove)*PerSkill, capped at Max when Max is set. It is
+// added first, then the score is scaled by Multiply when Multiply is set.
+type Rule struct {
+	Name              string    `yaml:"name"`
+	When              Condition `yaml:"when"`
+	Add               float64   `yaml:"add"`
+	Multiply          float64   `yaml:"multiply"`
+	PerYearExperience float64   `yaml:"per_year_experience"`
+	PerSkillAbove     int32     `yaml:"per_skill_above"`
+	PerSkill          float64   `yaml:"per_skill"`
+	Max               float64   `yaml:"max"`
+}
+
+// Model is a versioned set of weights and rules for calculating overall scores
+type Model struct {
+	Version     string   `yaml:"version"`
+	Description string   `yaml:"description"`
+	Positions   []string `yaml:"positions"`
+	Weights     Weights  `yaml:"weights"`
+	Rules       []Rule   `yaml:"rules"`
+}
+
+// BuiltinVersion is the version of the model compiled into the service
+const BuiltinVersion = "builtin-v1"
+
+// DefaultModel returns the built-in model, matching the original hard-coded formula
+func DefaultModel() *Model {
+	yes, no := true, false
+	return &Model{
+		Version:     BuiltinVersion,
+		Description: "Original scoring formula (definitely not biased)",
+		Weights: Weights{
+			Technical:   0.4,
+			Interview:   0.3,
+			CulturalFit: 0.3,
+		},
+		Rules: []Rule{
+			{
+				// Penalty for Java developers trying to write Go (we've all seen this)
+				Name:     "java_without_go",
+				When:     Condition{HasSkills: []string{"Java"}, KnowsGo: &no},
+				Multiply: 0.7,
+			},
+			{
+				// Bonus for being able to exit Vim (surprisingly rare skill)
+				Name: "can_exit_vim",
+				When: Condition{CanExitVim: &yes},
+				Add:  2.0,
+			},
+			{
+				// At least they're honest about it
+				Name: "debugs_in_production",
+				When: Condition{DebugsInProduction: &yes},
+				Add:  1.0,
+			},
+			{
+				// They're lying or incredibly lucky
+				Name: "never_debugs_in_production",
+				When: Condition{DebugsInProduction: &no, MinYearsExperience: 3},
+				Add:  -0.5,
+			},
+			{
+				// Experience boost (diminishing returns after 7 years)
+				Name:              "experience",
+				PerYearExperience: 0.5,
+				Max:               3.5,
+			},
+			{
+				// Skill diversity bonus
+				Name:          "skill_diversity",
+				PerSkillAbove: 5,
+				PerSkill:      0.2,
+				Max:           2.0,
+			},
+			{
+				// JavaScript developer trying to write Go? Oh boy...
+				Name:     "javascript_without_typescript_or_go",
+				When:     Condition{HasSkills: []string{"JavaScript"}, LacksSkills: []string{"TypeScript"}, KnowsGo: &no},
+				Multiply: 0.75,
+			},
+		},
+	}
+}
+
+// Adjustment records the effect of one applied rule on the running score
+type Adjustment struct {
+	Rule       string
+	Added      float64
+	Multiplier float64
+	Delta      float64
+	ScoreAfter float64
+}
+
+// Breakdown explains how a model arrived at an overall score
+type Breakdown struct {
+	ModelVersion   string
+	BaseScore      float64
+	Adjustments    []Adjustment
+	UnclampedScore float64
+	Clamped        bool
+	UnroundedScore float64
+	FinalScore     float64
+}
+
+// Score calculates the overall score (0-100, two decimals) for an applicant
+func (m *Model) Score(in Input) float64 {
+	return m.Explain(in).FinalScore
+}
+
+// Explain calculates the overall score for an applicant, recording every
+// rule that changed it along with clamping and rounding
+func (m *Model) Explain(in Input) Breakdown {
+	// Base score from the three main metrics
+	score := (in.TechnicalScore * m.Weights.Technical) +
+		(in.InterviewScore * m.Weights.Interview) +
+		(in.CulturalFitScore * m.Weights.CulturalFit)
+
+	b := Breakdown{
+		ModelVersion: m.Version,
+		BaseScore:    score,
+	}
+
+	for _, rule := range m.Rules {
+		if !rule.When.Matches(in) {
+			continue
+		}
+		added := rule.delta(in)
+		before := score
+		score = rule.apply(score, in)
+		if added == 0 && (rule.Multiply == 0 || rule.Multiply == 1) {
+			continue
+		}
+		b.Adjustments = append(b.Adjustments, Adjustment{
+			Rule:       rule.Name,
+			Added:      added,
+			Multiplier: rule.Multiply,
+			Delta:      score - before,
+			ScoreAfter: score,
+		})
+	}
+	b.UnclampedScore = score
+
+	// Ensure score is within valid range (0-100)
+	score = math.Max(0, math.Min(score, 100))
+	b.Clamped = score != b.UnclampedScore
+	b.UnroundedScore = score
+
+	// Round to 2 decimal places
+	b.FinalScore = math.Round(score*100) / 100
+
+	return b
+}
+
+// AppliesTo reports whether the model is configured for the given position
+func (m *Model) AppliesTo(position string) bool {
+	for _, p := range m.Positions {
+		if strings.EqualFold(strings.TrimSpace(p), strings.TrimSpace(position)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether the applicant satisfies every set field of the condition
+func (c Condition) Matches(in Input) bool {
+	for _, skill := range c.HasSkills {
+		if !hasSkill(in.Skills, skill) {
+			return false
+		}
+	}
+	for _, skill := range c.LacksSkills {
+		if hasSkill(in.Skills, skill) {
+			return false
+		}
+	}
+	if c.KnowsGo != nil && *c.KnowsGo != in.KnowsGo {
+		return false
+	}
+	if c.CanExitVim != nil && *c.CanExitVim != in.CanExitVim {
+		return false
+	}
+	if c.DebugsInProduction != nil && *c.DebugsInProduction != in.DebugsInProduction {
+		return false
+	}
+	return in.YearsExperience >= c.MinYearsExperience
+}
+
+// apply adds the rule's delta to score and then applies its multiplier
+func (r Rule) apply(score float64, in Input) float64 {
+	score += r.delta(in)
+	if r.Multiply != 0 {
+		score *= r.Multiply
+	}
+	return score
+}
+
+// delta returns the additive part of the rule for an applicant
+func (r Rule) delta(in Input) float64 {
+	delta := r.Add
+	if r.PerYearExperience != 0 {
+		delta += float64(in.YearsExperience) * r.PerYearExperience
+	}
+	if r.PerSkill != 0 && int32(len(in.Skills)) > r.PerSkillAbove {
+		delta += float64(int32(len(in.Skills))-r.PerSkillAbove) * r.PerSkill
+	}
+	if r.Max != 0 {
+		delta = math.Min(delta, r.Max)
+	}
+	return delta
+}
+
+// hasSkill checks if a skill is in the skills slice (case-insensitive)
+func hasSkill(skills []string, skill string) bool {
+	for _, s := range skills {
+		if strings.EqualFold(s, skill) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package scoring
+
+import (
+	"testing"
+)
+
+func TestModel_Score(t *testing.T) {
+	yes := true
+
+	model := &Model{
+		Version: "test",
+		Weights: Weights{Technical: 0.5, Interview: 0.25, CulturalFit: 0.25},
+		Rules: []Rule{
+			{Name: "kubernetes", When: Condition{HasSkills: []string{"kubernetes"}}, Add: 3.0},
+			{Name: "vim", When: Condition{CanExitVim: &yes}, Multiply: 1.1},
+			{Name: "experience", PerYearExperience: 1.0, Max: 4.0},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		input    Input
+		expected float64
+	}{
+		{
+			name:     "Weighted base only",
+			input:    Input{TechnicalScore: 80, InterviewScore: 60, CulturalFitScore: 40},
+			expected: 65.0,
+		},
+		{
+			name:     "Skill bonus matches case-insensitively",
+			input:    Input{Skills: []string{"Kubernetes"}, TechnicalScore: 80, InterviewScore: 60, CulturalFitScore: 40},
+			expected: 68.0,
+		},
+		{
+			name:     "Multiplier applies to running score",
+			input:    Input{CanExitVim: true, TechnicalScore: 80, InterviewScore: 60, CulturalFitScore: 40},
+			expected: 71.5,
+		},
+		{
+			name:     "Experience capped at max",
+			input:    Input{YearsExperience: 10, TechnicalScore: 80, InterviewScore: 60, CulturalFitScore: 40},
+			expected: 69.0,
+		},
+		{
+			name:     "Clamped to 100",
+			input:    Input{CanExitVim: true, YearsExperience: 10, TechnicalScore: 100, InterviewScore: 100, CulturalFitScore: 100},
+			expected: 100.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := model.Score(tt.input)
+			if result != tt.expected {
+				t.Errorf("Expected %.2f, got %.2f", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCondition_Matches(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name      string
+		condition Condition
+		input     Input
+		expected  bool
+	}{
+		{
+			name:      "Empty condition always matches",
+			condition: Condition{},
+			input:     Input{},
+			expected:  true,
+		},
+		{
+			name:      "Lacks skill present",
+			condition: Condition{HasSkills: []string{"JavaScript"}, LacksSkills: []string{"TypeScript"}},
+			input:     Input{Skills: []string{"javascript", "typescript"}},
+			expected:  false,
+		},
+		{
+			name:      "Boolean mismatch",
+			condition: Condition{KnowsGo: &no},
+			input:     Input{KnowsGo: true},
+			expected:  false,
+		},
+		{
+			name:      "All fields match",
+			condition: Condition{DebugsInProduction: &yes, MinYearsExperience: 3},
+			input:     Input{DebugsInProduction: true, YearsExperience: 3},
+			expected:  true,
+		},
+		{
+			name:      "Below minimum experience",
+			condition: Condition{MinYearsExperience: 3},
+			input:     Input{YearsExperience: 2},
+			expected:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.condition.Matches(tt.input); result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestDefaultModel_Validate(t *testing.T) {
+	if err := DefaultModel().Validate(); err != nil {
+		t.Errorf("Expected built-in model to be valid, got: %v", err)
+	}
+}
+
+func TestModel_Explain(t *testing.T) {
+	no := false
+
+	t.Run("Records applied rules, clamping and rounding", func(t *testing.T) {
+		b := DefaultModel().Explain(Input{
+			Skills:           []string{"Java"},
+			YearsExperience:  2,
+			InterviewScore:   80,
+			CulturalFitScore: 70,
+			TechnicalScore:   90,
+			CanExitVim:       true,
+		})
+
+		if b.ModelVersion != BuiltinVersion {
+			t.Errorf("Expected version %s, got %s", BuiltinVersion, b.ModelVersion)
+		}
+		if b.BaseScore != 81.0 {
+			t.Errorf("Expected base score 81.00, got %.4f", b.BaseScore)
+		}
+
+		expectedRules := []string{"java_without_go", "can_exit_vim", "experience"}
+		if len(b.Adjustments) != len(expectedRules) {
+			t.Fatalf("Expected adjustments %v, got %+v", expectedRules, b.Adjustments)
+		}
+		for i, rule := range expectedRules {
+			if b.Adjustments[i].Rule != rule {
+				t.Errorf("Expected adjustment %d to be %s, got %s", i, rule, b.Adjustments[i].Rule)
+			}
+		}
+		if b.Adjustments[0].Multiplier != 0.7 {
+			t.Errorf("Expected Java multiplier 0.7, got %.2f", b.Adjustments[0].Multiplier)
+		}
+		if b.Clamped {
+			t.Error("Expected score not to be clamped")
+		}
+		if b.FinalScore != 59.7 {
+			t.Errorf("Expected final score 59.70, got %.4f", b.FinalScore)
+		}
+	})
+
+	t.Run("Final score matches Score", func(t *testing.T) {
+		model := &Model{
+			Version: "clamp",
+			Weights: Weights{Technical: 1.0},
+			Rules: []Rule{
+				{Name: "bonus", When: Condition{KnowsGo: &no}, Add: 20.555},
+			},
+		}
+		in := Input{TechnicalScore: 95}
+
+		b := model.Explain(in)
+		if !b.Clamped {
+			t.Error("Expected score to be clamped")
+		}
+		if b.UnclampedScore <= 100 {
+			t.Errorf("Expected unclamped score above 100, got %.4f", b.UnclampedScore)
+		}
+		if b.FinalScore != model.Score(in) || b.FinalScore != 100 {
+			t.Errorf("Expected final score 100, got %.4f", b.FinalScore)
+		}
+	})
+}
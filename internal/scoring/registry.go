@@ -0,0 +1,159 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// File is the layout of a scoring rules file (YAML or JSON)
+type File struct {
+	Default string  `yaml:"default"`
+	Models  []Model `yaml:"models"`
+}
+
+// Registry holds the available scoring models and picks one per position
+type Registry struct {
+	models         []*Model
+	byVersion      map[string]*Model
+	defaultVersion string
+}
+
+// NewRegistry creates a registry from the given models in addition to the
+// built-in model. An empty defaultVersion selects the built-in model.
+func NewRegistry(models []Model, defaultVersion string) (*Registry, error) {
+	r := &Registry{
+		byVersion:      make(map[string]*Model),
+		defaultVersion: defaultVersion,
+	}
+	if r.defaultVersion == "" {
+		r.defaultVersion = BuiltinVersion
+	}
+
+	builtin := DefaultModel()
+	r.models = append(r.models, builtin)
+	r.byVersion[builtin.Version] = builtin
+
+	positions := make(map[string]string)
+	for i := range models {
+		m := &models[i]
+		if err := m.Validate(); err != nil {
+			return nil, fmt.Errorf("model %d: %w", i, err)
+		}
+		if _, exists := r.byVersion[m.Version]; exists {
+			return nil, fmt.Errorf("model %q: duplicate version", m.Version)
+		}
+		for _, p := range m.Positions {
+			key := strings.ToLower(strings.TrimSpace(p))
+			if other, taken := positions[key]; taken {
+				return nil, fmt.Errorf("model %q: position %q already assigned to model %q", m.Version, p, other)
+			}
+			positions[key] = m.Version
+		}
+		r.models = append(r.models, m)
+		r.byVersion[m.Version] = m
+	}
+
+	if _, ok := r.byVersion[r.defaultVersion]; !ok {
+		return nil, fmt.Errorf("default model %q not found", r.defaultVersion)
+	}
+
+	return r, nil
+}
+
+// LoadRegistry loads scoring models from a YAML or JSON rules file.
+// An empty path yields a registry containing only the built-in model.
+func LoadRegistry(path string) (*Registry, error) {
+	if path == "" {
+		return NewRegistry(nil, "")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open scoring rules: %w", err)
+	}
+	defer f.Close()
+
+	var file File
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse scoring rules %s: %w", path, err)
+	}
+
+	r, err := NewRegistry(file.Models, file.Default)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scoring rules %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// ForPosition returns the model configured for a position, or the default model.
+// A nil registry always returns the built-in model.
+func (r *Registry) ForPosition(position string) *Model {
+	if r == nil {
+		return DefaultModel()
+	}
+	for _, m := range r.models {
+		if m.AppliesTo(position) {
+			return m
+		}
+	}
+	return r.byVersion[r.defaultVersion]
+}
+
+// Get returns the model with the given version
+func (r *Registry) Get(version string) (*Model, bool) {
+	if r == nil {
+		if version == BuiltinVersion {
+			return DefaultModel(), true
+		}
+		return nil, false
+	}
+	m, ok := r.byVersion[version]
+	return m, ok
+}
+
+// List returns all models, built-in first, then in file order
+func (r *Registry) List() []*Model {
+	if r == nil {
+		return []*Model{DefaultModel()}
+	}
+	return r.models
+}
+
+// DefaultVersion returns the version used for positions without a dedicated model
+func (r *Registry) DefaultVersion() string {
+	if r == nil {
+		return BuiltinVersion
+	}
+	return r.defaultVersion
+}
+
+// Validate checks that a model is well formed
+func (m *Model) Validate() error {
+	if strings.TrimSpace(m.Version) == "" {
+		return errors.New("version is required")
+	}
+	if len(m.Version) > 64 {
+		return errors.New("version must be at most 64 characters")
+	}
+	if m.Weights.Technical < 0 || m.Weights.Interview < 0 || m.Weights.CulturalFit < 0 {
+		return errors.New("weights must not be negative")
+	}
+	for i, rule := range m.Rules {
+		if strings.TrimSpace(rule.Name) == "" {
+			return fmt.Errorf("rule %d: name is required", i)
+		}
+		if rule.Multiply < 0 {
+			return fmt.Errorf("rule %q: multiply must not be negative", rule.Name)
+		}
+		if rule.PerSkillAbove < 0 {
+			return fmt.Errorf("rule %q: per_skill_above must not be negative", rule.Name)
+		}
+	}
+	return nil
+}
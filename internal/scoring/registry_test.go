@@ -0,0 +1,115 @@
+package scoring
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeRules(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write rules file: %v", err)
+	}
+	return path
+}
+
+func TestLoadRegistry(t *testing.T) {
+	t.Run("Empty path uses built-in model", func(t *testing.T) {
+		r, err := LoadRegistry("")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if r.DefaultVersion() != BuiltinVersion {
+			t.Errorf("Expected default %s, got %s", BuiltinVersion, r.DefaultVersion())
+		}
+		if len(r.List()) != 1 {
+			t.Errorf("Expected 1 model, got %d", len(r.List()))
+		}
+	})
+
+	t.Run("YAML file with per-position model", func(t *testing.T) {
+		path := writeRules(t, "rules.yaml", `
+default: builtin-v1
+models:
+  - version: platform-v1
+    positions: [Platform Engineer]
+    weights: {technical: 1.0}
+    rules:
+      - name: kubernetes
+        when: {has_skills: [Kubernetes]}
+        add: 5
+`)
+		r, err := LoadRegistry(path)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		m := r.ForPosition("platform engineer")
+		if m.Version != "platform-v1" {
+			t.Fatalf("Expected platform-v1, got %s", m.Version)
+		}
+		if score := m.Score(Input{TechnicalScore: 80, Skills: []string{"kubernetes"}}); score != 85.0 {
+			t.Errorf("Expected 85.00, got %.2f", score)
+		}
+		if other := r.ForPosition("Backend Developer"); other.Version != BuiltinVersion {
+			t.Errorf("Expected fallback to %s, got %s", BuiltinVersion, other.Version)
+		}
+	})
+
+	t.Run("JSON file", func(t *testing.T) {
+		path := writeRules(t, "rules.json", `{
+  "default": "flat-v1",
+  "models": [{"version": "flat-v1", "weights": {"technical": 0.4, "interview": 0.3, "cultural_fit": 0.3}}]
+}`)
+		r, err := LoadRegistry(path)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if r.ForPosition("anything").Version != "flat-v1" {
+			t.Errorf("Expected flat-v1 as default")
+		}
+		if _, ok := r.Get(BuiltinVersion); !ok {
+			t.Errorf("Expected built-in model to remain available")
+		}
+	})
+
+	t.Run("Invalid files", func(t *testing.T) {
+		cases := map[string]string{
+			"unknown field":     "models:\n  - version: v1\n    wieghts: {}\n",
+			"missing version":   "models:\n  - description: no version\n",
+			"duplicate version": "models:\n  - version: builtin-v1\n",
+			"unknown default":   "default: nope\n",
+			"shared position":   "models:\n  - version: a\n    positions: [Dev]\n  - version: b\n    positions: [dev]\n",
+			"negative weight":   "models:\n  - version: v1\n    weights: {technical: -1}\n",
+		}
+		for name, content := range cases {
+			t.Run(name, func(t *testing.T) {
+				if _, err := LoadRegistry(writeRules(t, "rules.yaml", content)); err == nil {
+					t.Error("Expected error, got nil")
+				}
+			})
+		}
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		if _, err := LoadRegistry(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestRegistry_Nil(t *testing.T) {
+	var r *Registry
+
+	if r.ForPosition("Developer").Version != BuiltinVersion {
+		t.Error("Expected nil registry to use built-in model")
+	}
+	if _, ok := r.Get(BuiltinVersion); !ok {
+		t.Error("Expected nil registry to expose built-in model")
+	}
+	if r.DefaultVersion() != BuiltinVersion {
+		t.Error("Expected nil registry default to be built-in")
+	}
+}
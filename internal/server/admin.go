@@ -0,0 +1,16 @@
+package server
+
+import (
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// NewAdminHandler serves operational endpoints that are not part of the API
+// and must not be exposed to its clients. GET /log/level returns the current
+// log level and PUT /log/level with {"level":"debug"} changes it.
+func NewAdminHandler(level zap.AtomicLevel) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/log/level", level)
+	return mux
+}
@@ -4,24 +4,49 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"net/netip"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/metrics"
+	"github.com/Thrun12/golang-assignment/internal/ratelimit"
+	"github.com/Thrun12/golang-assignment/internal/rbac"
+	"github.com/Thrun12/golang-assignment/internal/requestid"
+	"github.com/Thrun12/golang-assignment/internal/util"
 )
 
 // swaggerSpec holds the loaded OpenAPI spec
 var swaggerSpec []byte
 
-// NewGatewayServer creates a new HTTP gateway server for the gRPC service
-func NewGatewayServer(ctx context.Context, grpcAddress string, corsOrigins []string, db *sql.DB, logger *zap.Logger) (http.Handler, error) {
+// watchPath is the REST route of WatchApplicants, which streams newline
+// delimited JSON events
+const watchPath = "/v1/applicants:watch"
+
+// NewGatewayServer creates a new HTTP gateway server for the gRPC service.
+// With m set, gateway requests are measured and /metrics serves m. The role
+// and agency headers are only forwarded from trustedProxies.
+func NewGatewayServer(ctx context.Context, grpcAddress string, corsOrigins []string, trustedProxies []netip.Prefix, db *sql.DB, m *metrics.Metrics, logger *zap.Logger) (http.Handler, error) {
 	// Load swagger spec if not already loaded
 	if len(swaggerSpec) == 0 {
 		data, err := os.ReadFile("api/proto/v1/applicants.swagger.json")
@@ -34,14 +59,22 @@ func NewGatewayServer(ctx context.Context, grpcAddress string, corsOrigins []str
 	}
 
 	// Create gRPC-Gateway mux
-	mux := runtime.NewServeMux(
+	muxOpts := []runtime.ServeMuxOption{
 		runtime.WithIncomingHeaderMatcher(customMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingMatcher),
 		runtime.WithErrorHandler(customErrorHandler),
-	)
+		runtime.WithForwardResponseOption(etagResponseOption),
+		runtime.WithMiddlewares(spanRouteMiddleware),
+	}
+	if m != nil {
+		muxOpts = append(muxOpts, runtime.WithMiddlewares(metricsMiddleware(m)))
+	}
+	mux := runtime.NewServeMux(muxOpts...)
 
 	// Setup connection options
 	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 	}
 
 	// Register gRPC-Gateway handlers
@@ -49,8 +82,8 @@ func NewGatewayServer(ctx context.Context, grpcAddress string, corsOrigins []str
 		return nil, fmt.Errorf("failed to register gateway: %w", err)
 	}
 
-	// Create HTTP handler with CORS
-	handler := corsMiddleware(mux, corsOrigins, logger)
+	// Create HTTP handler with CORS, traced from the moment a request arrives
+	handler := otelhttp.NewHandler(corsMiddleware(mux, corsOrigins, logger), "gateway")
 
 	// Add health check and swagger endpoints
 	healthMux := http.NewServeMux()
@@ -60,34 +93,300 @@ func NewGatewayServer(ctx context.Context, grpcAddress string, corsOrigins []str
 	healthMux.HandleFunc("/swagger.json", swaggerJSONHandler)
 	healthMux.HandleFunc("/docs", docsRedirectHandler)
 	healthMux.HandleFunc("/docs/", swaggerUIHandler)
+	if m != nil {
+		healthMux.Handle("/metrics", m.Handler())
+	}
 
 	// Combine handlers
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	return requestIDMiddleware(identityHeaderMiddleware(trustedProxies, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if strings.HasPrefix(r.URL.Path, "/health") || strings.HasPrefix(r.URL.Path, "/ready") {
 			healthMux.ServeHTTP(w, r)
 			return
 		}
-		if strings.HasPrefix(r.URL.Path, "/docs") || strings.HasPrefix(r.URL.Path, "/swagger") {
+		if strings.HasPrefix(r.URL.Path, "/docs") || strings.HasPrefix(r.URL.Path, "/swagger") || r.URL.Path == "/metrics" {
 			healthMux.ServeHTTP(w, r)
 			return
 		}
+		if r.URL.Path == watchPath {
+			// Watch streams stay open far longer than the server's write timeout
+			if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+				logger.Warn("failed to lift write deadline of watch stream", zap.Error(err))
+			}
+		}
 		handler.ServeHTTP(w, r)
-	}), nil
+	}))), nil
+}
+
+// identityHeaderMiddleware drops the role and agency headers from requests
+// that do not come from one of trustedProxies, so that REST callers cannot
+// claim roles or an agency themselves
+func identityHeaderMiddleware(trustedProxies []netip.Prefix, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !fromTrustedProxy(r, trustedProxies) {
+			r.Header.Del(rbac.RoleHeader)
+			r.Header.Del(rbac.AgencyHeader)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// fromTrustedProxy reports whether r was sent from one of trustedProxies
+func fromTrustedProxy(r *http.Request, trustedProxies []netip.Prefix) bool {
+	if len(trustedProxies) == 0 {
+		return false
+	}
+	addrPort, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	addr := addrPort.Addr().Unmap()
+	for _, proxy := range trustedProxies {
+		if proxy.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestIDMiddleware makes sure every request carries a valid X-Request-ID,
+// generating one if the client sent none, and echoes it on the response. The
+// header is forwarded to the gRPC server, which logs and records the same ID.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+			r.Header.Set(requestid.Header, id)
+		}
+		w.Header().Set(requestid.Header, id)
+
+		next.ServeHTTP(w, r)
+	})
 }
 
 // customMatcher matches incoming HTTP headers to gRPC metadata
 func customMatcher(key string) (string, bool) {
 	switch strings.ToLower(key) {
-	case "x-request-id":
+	case requestid.Header, "traceparent", "tracestate", "x-api-key", "if-match", "if-none-match", rbac.RoleHeader, rbac.AgencyHeader:
 		return key, true
 	default:
 		return runtime.DefaultHeaderMatcher(key)
 	}
 }
 
-// customErrorHandler handles errors from gRPC-Gateway
+// spanRouteMiddleware names the request span after the route template it
+// matched, e.g. "GET /v1/applicants/{id=*}", instead of the generic "gateway"
+func spanRouteMiddleware(next runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		if pattern, ok := runtime.HTTPPattern(r.Context()); ok {
+			route := pattern.String()
+			span := trace.SpanFromContext(r.Context())
+			span.SetName(r.Method + " " + route)
+			span.SetAttributes(semconv.HTTPRoute(route))
+		}
+		next(w, r, pathParams)
+	}
+}
+
+// metricsMiddleware records every request routed by the gateway mux, labelled
+// with the route template it matched
+func metricsMiddleware(m *metrics.Metrics) runtime.Middleware {
+	return func(next runtime.HandlerFunc) runtime.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+			start := time.Now()
+			route := "unknown"
+			if pattern, ok := runtime.HTTPPattern(r.Context()); ok {
+				route = pattern.String()
+			}
+
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next(rec, r, pathParams)
+			m.ObserveHTTP(r.Method, route, rec.status, time.Since(start))
+		}
+	}
+}
+
+// statusRecorder remembers the status code written to a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Flush lets streamed responses through the recorder
+func (w *statusRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// outgoingMatcher maps gRPC response header metadata to HTTP headers. Rate limit
+// metadata is forwarded as plain X-RateLimit-* headers and the request ID is
+// dropped; everything else keeps the default Grpc-Metadata- prefix.
+func outgoingMatcher(key string) (string, bool) {
+	switch strings.ToLower(key) {
+	case ratelimit.LimitHeader, ratelimit.RemainingHeader, ratelimit.ResetHeader:
+		return key, true
+	case requestid.Header:
+		// Already set on every response by requestIDMiddleware
+		return "", false
+	default:
+		return runtime.MetadataHeaderPrefix + key, true
+	}
+}
+
+// errNotModified signals that a GET matched the client's If-None-Match header
+var errNotModified = errors.New("not modified")
+
+// applicantResponse is implemented by responses carrying a single applicant
+type applicantResponse interface {
+	GetApplicant() *applicantsv1.JobApplicant
+}
+
+// etagResponseOption sets the ETag header on responses carrying a single
+// applicant, and answers a GET whose If-None-Match header matches it with 304
+func etagResponseOption(ctx context.Context, w http.ResponseWriter, resp proto.Message) error {
+	if _, isWatch := resp.(*applicantsv1.WatchApplicantsResponse); isWatch {
+		// Streamed events share one response, which has no single ETag
+		return nil
+	}
+	r, ok := resp.(applicantResponse)
+	if !ok || r.GetApplicant().GetEtag() == "" {
+		return nil
+	}
+	etag := r.GetApplicant().GetEtag()
+	w.Header().Set("ETag", `"`+etag+`"`)
+
+	if _, isGet := resp.(*applicantsv1.GetApplicantResponse); !isGet {
+		return nil
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	for _, condition := range md.Get("if-none-match") {
+		if util.ETagMatches(condition, etag) {
+			return errNotModified
+		}
+	}
+	return nil
+}
+
+// statusOverrideWriter replaces the status code written by the default error handler
+type statusOverrideWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusOverrideWriter) WriteHeader(int) {
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// fieldViolationJSON is a single invalid field in an error response
+type fieldViolationJSON struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// customErrorHandler handles errors from gRPC-Gateway. Errors carrying
+// BadRequest field violations are rendered with a "violations" array of
+// {field, description} objects; everything else uses the default handler.
+// Etag mismatches are returned as 412 Precondition Failed, unauthenticated
+// requests carry a WWW-Authenticate challenge and rate limited requests a
+// Retry-After header.
 func customErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
-	runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+	if errors.Is(err, errNotModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	st := status.Convert(err)
+	if st.Code() == codes.Unauthenticated {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	if retryAfter, ok := retryDelay(st); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+	if isETagMismatch(st) {
+		w = &statusOverrideWriter{ResponseWriter: w, status: http.StatusPreconditionFailed}
+	}
+
+	var violations []fieldViolationJSON
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, v := range badRequest.GetFieldViolations() {
+			violations = append(violations, fieldViolationJSON{
+				Field:       jsonFieldName(v.GetField()),
+				Description: v.GetDescription(),
+			})
+		}
+	}
+
+	if len(violations) == 0 {
+		runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(runtime.HTTPStatusFromCode(st.Code()))
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":       st.Code(),
+		"message":    st.Message(),
+		"violations": violations,
+	})
+}
+
+// isETagMismatch reports whether st rejects a request for carrying a stale etag
+func isETagMismatch(st *status.Status) bool {
+	if st.Code() != codes.Aborted {
+		return false
+	}
+	for _, detail := range st.Details() {
+		failure, ok := detail.(*errdetails.PreconditionFailure)
+		if !ok {
+			continue
+		}
+		for _, v := range failure.GetViolations() {
+			if v.GetType() == util.ETagPreconditionType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// retryDelay returns the delay of a RetryInfo detail of st, if any
+func retryDelay(st *status.Status) (time.Duration, bool) {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return 0, false
+}
+
+// jsonFieldName converts a proto field path such as "years_experience" to the
+// lowerCamelCase name used in REST request bodies
+func jsonFieldName(field string) string {
+	var b strings.Builder
+	upper := false
+	for _, c := range field {
+		if c == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			b.WriteString(strings.ToUpper(string(c)))
+			upper = false
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
 }
 
 // corsMiddleware adds CORS headers to responses
@@ -110,8 +409,9 @@ func corsMiddleware(next http.Handler, allowedOrigins []string, logger *zap.Logg
 			} else if len(allowedOrigins) > 0 && allowedOrigins[0] == "*" {
 				w.Header().Set("Access-Control-Allow-Origin", "*")
 			}
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Request-ID, If-Match, If-None-Match, traceparent, tracestate")
+			w.Header().Set("Access-Control-Expose-Headers", "ETag, X-Request-ID, Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset")
 			w.Header().Set("Access-Control-Max-Age", "3600")
 		}
 
@@ -0,0 +1,282 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/metrics"
+	"github.com/Thrun12/golang-assignment/internal/rbac"
+	"github.com/Thrun12/golang-assignment/internal/util"
+)
+
+func TestCustomErrorHandler(t *testing.T) {
+	mux := runtime.NewServeMux()
+	marshaler := &runtime.JSONPb{}
+
+	t.Run("Field violations are rendered as an array", func(t *testing.T) {
+		st, err := status.New(codes.InvalidArgument, "validation failed").WithDetails(&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: "email", Description: "email is required"},
+				{Field: "years_experience", Description: "years_experience must be positive"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to build status: %v", err)
+		}
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/v1/applicants", nil)
+		customErrorHandler(context.Background(), mux, marshaler, w, r, st.Err())
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+
+		var body struct {
+			Message    string `json:"message"`
+			Violations []struct {
+				Field       string `json:"field"`
+				Description string `json:"description"`
+			} `json:"violations"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Expected JSON body, got %q: %v", w.Body.String(), err)
+		}
+		if len(body.Violations) != 2 {
+			t.Fatalf("Expected 2 violations, got %d", len(body.Violations))
+		}
+		if body.Violations[1].Field != "yearsExperience" {
+			t.Errorf("Expected field 'yearsExperience', got '%s'", body.Violations[1].Field)
+		}
+		if body.Violations[0].Description != "email is required" {
+			t.Errorf("Expected description 'email is required', got '%s'", body.Violations[0].Description)
+		}
+	})
+
+	t.Run("Other errors use the default handler", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/v1/applicants/1", nil)
+		customErrorHandler(context.Background(), mux, marshaler, w, r, status.Error(codes.NotFound, "applicant not found: 1"))
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+		if !json.Valid(w.Body.Bytes()) {
+			t.Errorf("Expected JSON body, got %q", w.Body.String())
+		}
+	})
+}
+
+func TestETagResponseOption(t *testing.T) {
+	resp := &applicantsv1.GetApplicantResponse{
+		Applicant: &applicantsv1.JobApplicant{Id: 1, Etag: "7"},
+	}
+
+	t.Run("Sets ETag header", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		if err := etagResponseOption(context.Background(), w, resp); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if got := w.Header().Get("ETag"); got != `"7"` {
+			t.Errorf(`Expected ETag "7", got %s`, got)
+		}
+	})
+
+	t.Run("Matching If-None-Match is not modified", func(t *testing.T) {
+		ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("if-none-match", `"7"`))
+		err := etagResponseOption(ctx, httptest.NewRecorder(), resp)
+		if !errors.Is(err, errNotModified) {
+			t.Fatalf("Expected errNotModified, got %v", err)
+		}
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/v1/applicants/1", nil)
+		customErrorHandler(ctx, runtime.NewServeMux(), &runtime.JSONPb{}, w, r, err)
+		if w.Code != http.StatusNotModified {
+			t.Errorf("Expected status 304, got %d", w.Code)
+		}
+	})
+
+	t.Run("Stale If-None-Match returns the applicant", func(t *testing.T) {
+		ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("if-none-match", `"6"`))
+		if err := etagResponseOption(ctx, httptest.NewRecorder(), resp); err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+	})
+}
+
+func TestCustomErrorHandler_ETagMismatch(t *testing.T) {
+	st, err := status.New(codes.Aborted, "applicant was modified").WithDetails(&errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{
+			{Type: util.ETagPreconditionType, Subject: "applicant/1"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to build status: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPut, "/v1/applicants/1", nil)
+	customErrorHandler(context.Background(), runtime.NewServeMux(), &runtime.JSONPb{}, w, r, st.Err())
+
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected status 412, got %d", w.Code)
+	}
+
+	// Other aborted requests keep the default mapping
+	w = httptest.NewRecorder()
+	customErrorHandler(context.Background(), runtime.NewServeMux(), &runtime.JSONPb{}, w, r, status.Error(codes.Aborted, "concurrent modification"))
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409, got %d", w.Code)
+	}
+}
+
+func TestCustomErrorHandler_RateLimited(t *testing.T) {
+	st, err := status.New(codes.ResourceExhausted, "rate limit exceeded").WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(1500 * time.Millisecond),
+	})
+	if err != nil {
+		t.Fatalf("failed to build status: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/v1/applicants", nil)
+	customErrorHandler(context.Background(), runtime.NewServeMux(), &runtime.JSONPb{}, w, r, st.Err())
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Expected Retry-After 2, got %q", got)
+	}
+}
+
+func TestOutgoingMatcher(t *testing.T) {
+	if key, ok := outgoingMatcher("x-ratelimit-remaining"); !ok || key != "x-ratelimit-remaining" {
+		t.Errorf("Expected rate limit header to be forwarded as is, got %q", key)
+	}
+	if key, ok := outgoingMatcher("x-custom"); !ok || key != runtime.MetadataHeaderPrefix+"x-custom" {
+		t.Errorf("Expected other metadata to keep the default prefix, got %q", key)
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var forwarded string
+	handler := requestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Get("X-Request-ID")
+	}))
+
+	t.Run("Client ID is echoed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/v1/applicants", nil)
+		r.Header.Set("X-Request-ID", "client-42")
+		handler.ServeHTTP(w, r)
+
+		if forwarded != "client-42" || w.Header().Get("X-Request-ID") != "client-42" {
+			t.Errorf("Expected client-42 to be forwarded and echoed, got %q and %q", forwarded, w.Header().Get("X-Request-ID"))
+		}
+	})
+
+	t.Run("Missing ID is generated", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/applicants", nil))
+
+		id := w.Header().Get("X-Request-ID")
+		if id == "" || forwarded != id {
+			t.Errorf("Expected generated ID to be forwarded and echoed, got %q and %q", forwarded, id)
+		}
+	})
+}
+
+func TestIdentityHeaderMiddleware(t *testing.T) {
+	var roles, agency string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		roles, agency = r.Header.Get(rbac.RoleHeader), r.Header.Get(rbac.AgencyHeader)
+	})
+	proxy := netip.MustParsePrefix("10.0.0.0/24")
+
+	tests := []struct {
+		name           string
+		trustedProxies []netip.Prefix
+		remoteAddr     string
+		forwarded      bool
+	}{
+		{name: "Dropped by default", remoteAddr: "10.0.0.5:41000"},
+		{name: "Dropped from other addresses", trustedProxies: []netip.Prefix{proxy}, remoteAddr: "203.0.113.7:41000"},
+		{name: "Forwarded from a trusted proxy", trustedProxies: []netip.Prefix{proxy}, remoteAddr: "10.0.0.5:41000", forwarded: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/v1/applicants", nil)
+			r.RemoteAddr = tt.remoteAddr
+			r.Header.Set(rbac.RoleHeader, "admin")
+			r.Header.Set(rbac.AgencyHeader, "talent-co")
+			identityHeaderMiddleware(tt.trustedProxies, next).ServeHTTP(httptest.NewRecorder(), r)
+
+			if forwarded := roles == "admin" && agency == "talent-co"; forwarded != tt.forwarded {
+				t.Errorf("Expected headers forwarded: %v, got roles %q and agency %q", tt.forwarded, roles, agency)
+			}
+		})
+	}
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	m := metrics.New()
+	mux := runtime.NewServeMux(runtime.WithMiddlewares(metricsMiddleware(m)))
+	if err := mux.HandlePath(http.MethodGet, "/v1/applicants/{id}", func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		w.WriteHeader(http.StatusNotFound)
+	}); err != nil {
+		t.Fatalf("failed to register handler: %v", err)
+	}
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/applicants/42", nil))
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	expected := `applicants_http_requests_total{code="404",method="GET",route="/v1/applicants/{id=*}"} 1`
+	if !strings.Contains(w.Body.String(), expected) {
+		t.Errorf("Expected %s in:\n%s", expected, w.Body.String())
+	}
+}
+
+func TestSpanRouteMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	mux := runtime.NewServeMux(runtime.WithMiddlewares(spanRouteMiddleware))
+	if err := mux.HandlePath(http.MethodGet, "/v1/applicants/{id}", func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		w.WriteHeader(http.StatusOK)
+	}); err != nil {
+		t.Fatalf("failed to register handler: %v", err)
+	}
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "gateway")
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/applicants/42", nil).WithContext(ctx))
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	if name := spans[0].Name(); name != "GET /v1/applicants/{id=*}" {
+		t.Errorf("Expected span name GET /v1/applicants/{id=*}, got %q", name)
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/auth"
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+	"github.com/Thrun12/golang-assignment/internal/util"
+)
+
+// apiKeyResource identifies an API key in error details
+func apiKeyResource(id int64) *errdetails.ResourceInfo {
+	return &errdetails.ResourceInfo{
+		ResourceType: "api_key",
+		ResourceName: strconv.FormatInt(id, 10),
+	}
+}
+
+// CreateApiKey issues a new API key. Only its hash is stored, so the key in
+// the response is the only copy.
+func (s *ApplicantService) CreateApiKey(ctx context.Context, req *applicantsv1.CreateApiKeyRequest) (*applicantsv1.CreateApiKeyResponse, error) {
+	// Validate input
+	verr := &util.ValidationError{}
+	if strings.TrimSpace(req.Name) == "" {
+		verr.Add("name", "name is required")
+	} else if len(req.Name) > 255 {
+		verr.Add("name", "name must be at most 255 characters")
+	}
+	if strings.TrimSpace(req.Owner) == "" {
+		verr.Add("owner", "owner is required")
+	} else if len(req.Owner) > 255 {
+		verr.Add("owner", "owner must be at most 255 characters")
+	}
+	if len(req.Scopes) == 0 {
+		verr.Add("scopes", "scopes must contain at least one role")
+	}
+	for _, scope := range req.Scopes {
+		if strings.TrimSpace(scope) == "" {
+			verr.Add("scopes", "scopes must not be empty")
+			break
+		}
+	}
+	var expiresAt sql.NullTime
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.AsTime().After(time.Now()) {
+			verr.Add("expires_at", "expires_at must be in the future")
+		}
+		expiresAt = sql.NullTime{Time: req.ExpiresAt.AsTime(), Valid: true}
+	}
+	if err := verr.Err(); err != nil {
+		return nil, invalidRequest(err)
+	}
+
+	generated, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, s.translateError(ctx, err, "create API key", nil)
+	}
+
+	apiKey, err := s.queries.CreateApiKey(ctx, sqlc.CreateApiKeyParams{
+		Name:      req.Name,
+		Owner:     req.Owner,
+		KeyPrefix: generated.Prefix,
+		KeyHash:   generated.Hash,
+		Scopes:    req.Scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, s.translateError(ctx, err, "create API key", nil)
+	}
+
+	s.log(ctx).Info("API key created",
+		zap.Int64("id", apiKey.ID),
+		zap.String("owner", apiKey.Owner),
+		zap.Strings("scopes", apiKey.Scopes),
+	)
+
+	return &applicantsv1.CreateApiKeyResponse{
+		ApiKey: util.ApiKeyToProto(&apiKey),
+		Key:    generated.Key,
+	}, nil
+}
+
+// RevokeApiKey revokes an API key; requests using it fail from then on
+func (s *ApplicantService) RevokeApiKey(ctx context.Context, req *applicantsv1.RevokeApiKeyRequest) (*applicantsv1.RevokeApiKeyResponse, error) {
+	// Validate input
+	if req.Id <= 0 {
+		return nil, invalidField("id", "id must be positive")
+	}
+
+	apiKey, err := s.queries.RevokeApiKey(ctx, req.Id)
+	if err != nil {
+		// Already revoked keys are reported as not found
+		return nil, s.translateError(ctx, err, "revoke API key", apiKeyResource(req.Id))
+	}
+
+	s.log(ctx).Info("API key revoked",
+		zap.Int64("id", apiKey.ID),
+		zap.String("owner", apiKey.Owner),
+	)
+
+	return &applicantsv1.RevokeApiKeyResponse{
+		ApiKey: util.ApiKeyToProto(&apiKey),
+	}, nil
+}
+
+// ListApiKeys lists all API keys, newest first, without their secrets
+func (s *ApplicantService) ListApiKeys(ctx context.Context, req *applicantsv1.ListApiKeysRequest) (*applicantsv1.ListApiKeysResponse, error) {
+	apiKeys, err := s.queries.ListApiKeys(ctx)
+	if err != nil {
+		return nil, s.translateError(ctx, err, "list API keys", nil)
+	}
+
+	resp := &applicantsv1.ListApiKeysResponse{
+		ApiKeys: make([]*applicantsv1.ApiKey, len(apiKeys)),
+	}
+	for i := range apiKeys {
+		resp.ApiKeys[i] = util.ApiKeyToProto(&apiKeys[i])
+	}
+	return resp, nil
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/auth"
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+	"github.com/Thrun12/golang-assignment/internal/logging"
+	"github.com/Thrun12/golang-assignment/internal/requestid"
+	"github.com/Thrun12/golang-assignment/internal/util"
+)
+
+// systemActor is recorded for changes made without a caller, e.g. by the rescore command
+const systemActor = "system"
+
+// auditIgnoredFields are bookkeeping fields left out of audit diffs
+var auditIgnoredFields = map[string]bool{
+	"id":              true,
+	"etag":            true,
+	"created_at":      true,
+	"updated_at":      true,
+	"score_breakdown": true,
+}
+
+// auditMaskedFields are the JobApplicant fields marked (sensitive). The audit log
+// only records that they changed, never their values, so an applicant's personal
+// data is gone once it is purged.
+var auditMaskedFields = sensitiveFields(&applicantsv1.JobApplicant{})
+
+// fieldChange is the value of a changed field before and after a mutation
+type fieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// recordAudit appends a mutation of an applicant to the audit log using q, so
+// it commits or rolls back with the change itself. before is nil for creations;
+// both are nil when the applicant's data must not be kept, as for purges.
+func (s *ApplicantService) recordAudit(ctx context.Context, q sqlc.Querier, method string, applicantID int64, before, after *sqlc.Applicant) error {
+	changes, err := auditChanges(before, after)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateAuditEvent(ctx, sqlc.CreateAuditEventParams{
+		ApplicantID: applicantID,
+		Actor:       auditActor(ctx),
+		RequestID:   requestid.FromContext(ctx),
+		Method:      method,
+		Changes:     changes,
+	})
+	return err
+}
+
+// auditChanges returns the fields that differ between before and after as a
+// JSON object of {"field": {"before": ..., "after": ...}}
+func auditChanges(before, after *sqlc.Applicant) (json.RawMessage, error) {
+	beforeFields, err := auditSnapshot(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditSnapshot(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]fieldChange)
+	for name, value := range afterFields {
+		if old, ok := beforeFields[name]; !ok || !reflect.DeepEqual(old, value) {
+			changes[name] = fieldChange{Before: beforeFields[name], After: value}
+		}
+	}
+	for name, old := range beforeFields {
+		if _, ok := afterFields[name]; !ok {
+			changes[name] = fieldChange{Before: old}
+		}
+	}
+	for name, change := range changes {
+		if auditMaskedFields[name] {
+			changes[name] = fieldChange{Before: maskValue(change.Before), After: maskValue(change.After)}
+		}
+	}
+
+	return json.Marshal(changes)
+}
+
+// maskValue replaces a non-empty value of a masked field with logging.Redacted
+func maskValue(value interface{}) interface{} {
+	if value == nil || value == "" {
+		return value
+	}
+	return logging.Redacted
+}
+
+// sensitiveFields returns the proto names of the fields of msg marked (sensitive)
+func sensitiveFields(msg proto.Message) map[string]bool {
+	names := make(map[string]bool)
+	fields := msg.ProtoReflect().Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if sensitive, _ := proto.GetExtension(fd.Options(), applicantsv1.E_Sensitive).(bool); sensitive {
+			names[string(fd.Name())] = true
+		}
+	}
+	return names
+}
+
+// auditSnapshot returns the audited fields of an applicant by proto field name
+func auditSnapshot(applicant *sqlc.Applicant) (map[string]interface{}, error) {
+	if applicant == nil {
+		return nil, nil
+	}
+
+	data, err := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}.Marshal(util.DbApplicantToProto(applicant))
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot applicant: %w", err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to snapshot applicant: %w", err)
+	}
+	for name := range auditIgnoredFields {
+		delete(fields, name)
+	}
+	fields["deleted"] = applicant.DeletedAt.Valid
+
+	return fields, nil
+}
+
+// auditActor identifies the caller making a change
+func auditActor(ctx context.Context) string {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return systemActor
+	}
+	if principal.Subject == "" {
+		return "anonymous"
+	}
+	return principal.Subject
+}
+
+// ListAuditEvents lists recorded applicant mutations, newest first
+func (s *ApplicantService) ListAuditEvents(ctx context.Context, req *applicantsv1.ListAuditEventsRequest) (*applicantsv1.ListAuditEventsResponse, error) {
+	// Validate input
+	verr := &util.ValidationError{}
+	if req.ApplicantId < 0 {
+		verr.Add("applicant_id", "applicant_id must not be negative")
+	}
+	if req.StartTime != nil && req.EndTime != nil && !req.EndTime.AsTime().After(req.StartTime.AsTime()) {
+		verr.Add("end_time", "end_time must be after start_time")
+	}
+	var beforeID int64
+	if req.PageToken != "" {
+		cursor, err := util.DecodeAuditPageToken(req.PageToken)
+		if err != nil {
+			verr.Add("page_token", fmt.Sprintf("page_token is invalid: %v", err))
+		}
+		beforeID = cursor.ID
+	}
+	if err := verr.Err(); err != nil {
+		return nil, invalidRequest(err)
+	}
+
+	limit := req.Limit
+	if limit < 1 {
+		limit = 50
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	s.log(ctx).Debug("listing audit events",
+		zap.Int64("applicant_id", req.ApplicantId),
+		zap.String("actor", req.Actor),
+		zap.Int32("limit", limit),
+	)
+
+	// Fetch one extra event to know whether there is a next page
+	events, err := s.queries.ListAuditEvents(ctx, sqlc.ListAuditEventsParams{
+		ApplicantID: req.ApplicantId,
+		Actor:       req.Actor,
+		StartTime:   timestampToNullTime(req.StartTime),
+		EndTime:     timestampToNullTime(req.EndTime),
+		BeforeID:    beforeID,
+		PageSize:    limit + 1,
+	})
+	if err != nil {
+		return nil, s.translateError(ctx, err, "list audit events", nil)
+	}
+
+	resp := &applicantsv1.ListAuditEventsResponse{}
+	if len(events) > int(limit) {
+		events = events[:limit]
+		last := events[len(events)-1]
+		resp.NextPageToken = util.EncodeAuditPageToken(util.AuditCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	resp.Events = make([]*applicantsv1.AuditEvent, len(events))
+	for i := range events {
+		event, err := auditEventToProto(&events[i])
+		if err != nil {
+			return nil, s.translateError(ctx, err, "list audit events", nil)
+		}
+		resp.Events[i] = event
+	}
+	return resp, nil
+}
+
+// auditEventToProto converts a database audit event to protobuf format
+func auditEventToProto(event *sqlc.ApplicantAuditLog) (*applicantsv1.AuditEvent, error) {
+	changes := &structpb.Struct{}
+	if err := protojson.Unmarshal(event.Changes, changes); err != nil {
+		return nil, fmt.Errorf("failed to decode audit changes of event %d: %w", event.ID, err)
+	}
+
+	return &applicantsv1.AuditEvent{
+		Id:          event.ID,
+		ApplicantId: event.ApplicantID,
+		Actor:       event.Actor,
+		RequestId:   event.RequestID,
+		Method:      event.Method,
+		Changes:     changes,
+		CreatedAt:   timestamppb.New(event.CreatedAt),
+	}, nil
+}
+
+// timestampToNullTime converts an optional timestamp to sql.NullTime
+func timestampToNullTime(ts *timestamppb.Timestamp) sql.NullTime {
+	if ts == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: ts.AsTime(), Valid: true}
+}
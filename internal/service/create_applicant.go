@@ -2,40 +2,41 @@ package service
 
 import (
 	"context"
-	"strings"
 
-	"github.com/lib/pq"
 	"go.uber.org/zap"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
 	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/auth"
 	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+	"github.com/Thrun12/golang-assignment/internal/scoring"
 	"github.com/Thrun12/golang-assignment/internal/util"
 )
 
-// CreateApplicant creates a new applicant with calculated overall score
+// CreateApplicant creates a new applicant with calculated overall score. The
+// request's field rules are enforced by the ValidationInterceptor.
 func (s *ApplicantService) CreateApplicant(ctx context.Context, req *applicantsv1.CreateApplicantRequest) (*applicantsv1.CreateApplicantResponse, error) {
-	// Validate input
-	if err := util.ValidateApplicant(req.Name, req.Email, req.Position, req.YearsExperience, req.GithubStars, req.InterviewScore, req.CulturalFitScore, req.TechnicalScore, false, 0); err != nil {
-		s.logger.Debug("validation failed", zap.Error(err))
-		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	// Applicants enter the workflow as APPLIED; later statuses are reached
+	// through TransitionApplicantStatus
+	if req.Status != applicantsv1.ApplicantStatus_APPLICANT_STATUS_UNSPECIFIED && req.Status != applicantsv1.ApplicantStatus_APPLICANT_STATUS_APPLIED {
+		return nil, invalidField("status", "new applicants start as APPLICANT_STATUS_APPLIED, use TransitionApplicantStatus to change it")
 	}
 
-	s.logger.Debug("creating applicant", zap.String("email", req.Email))
+	s.log(ctx).Debug("creating applicant", zap.String("position", req.Position))
 
 	// Calculate overall score using our sophisticated (totally unbiased) algorithm
-	overallScore := util.CalculateOverallScore(
-		req.Name,
-		req.Skills,
-		req.YearsExperience,
-		req.InterviewScore,
-		req.CulturalFitScore,
-		req.TechnicalScore,
-		req.CanExitVim,
-		req.KnowsGo,
-		req.DebugsInProduction,
-	)
+	model := s.scoringModels.ForPosition(req.Position)
+	overallScore := model.Score(scoring.Input{
+		Name:               req.Name,
+		Position:           req.Position,
+		Skills:             req.Skills,
+		YearsExperience:    req.YearsExperience,
+		InterviewScore:     req.InterviewScore,
+		CulturalFitScore:   req.CulturalFitScore,
+		TechnicalScore:     req.TechnicalScore,
+		CanExitVim:         req.CanExitVim,
+		KnowsGo:            req.KnowsGo,
+		DebugsInProduction: req.DebugsInProduction,
+	})
 
 	// Prepare optional fields
 	var funFact, availability, salaryExpectation *string
@@ -49,42 +50,50 @@ func (s *ApplicantService) CreateApplicant(ctx context.Context, req *applicantsv
 		salaryExpectation = &req.SalaryExpectation
 	}
 
-	// Create applicant
-	applicant, err := s.queries.CreateApplicant(ctx, sqlc.CreateApplicantParams{
-		Name:               req.Name,
-		Email:              req.Email,
-		Position:           req.Position,
-		YearsExperience:    req.YearsExperience,
-		Skills:             req.Skills,
-		GithubStars:        req.GithubStars,
-		CanExitVim:         req.CanExitVim,
-		KnowsGo:            req.KnowsGo,
-		DebugsInProduction: req.DebugsInProduction,
-		InterviewScore:     req.InterviewScore,
-		CulturalFitScore:   req.CulturalFitScore,
-		TechnicalScore:     req.TechnicalScore,
-		OverallScore:       overallScore,
-		Status:             int32(req.Status),
-		FunFact:            util.ToNullString(funFact),
-		Availability:       util.ToNullString(availability),
-		SalaryExpectation:  util.ToNullString(salaryExpectation),
-	})
+	// Applicants submitted by an agency belong to that agency
+	var agency *string
+	if principal, ok := auth.FromContext(ctx); ok && principal.Agency != "" {
+		agency = &principal.Agency
+	}
 
-	if err != nil {
-		// Check for unique constraint violation on email
-		if pqErr, ok := err.(*pq.Error); ok {
-			if pqErr.Code == "23505" && strings.Contains(pqErr.Constraint, "email") {
-				s.logger.Error("email already exists", zap.String("email", req.Email))
-				return nil, status.Errorf(codes.AlreadyExists, "email address already exists: %s", req.Email)
-			}
+	// Create applicant and record it in the audit log
+	var applicant sqlc.Applicant
+	err := s.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		var err error
+		applicant, err = q.CreateApplicant(ctx, sqlc.CreateApplicantParams{
+			Name:                req.Name,
+			Email:               req.Email,
+			Position:            req.Position,
+			YearsExperience:     req.YearsExperience,
+			Skills:              req.Skills,
+			GithubStars:         req.GithubStars,
+			CanExitVim:          req.CanExitVim,
+			KnowsGo:             req.KnowsGo,
+			DebugsInProduction:  req.DebugsInProduction,
+			InterviewScore:      req.InterviewScore,
+			CulturalFitScore:    req.CulturalFitScore,
+			TechnicalScore:      req.TechnicalScore,
+			OverallScore:        overallScore,
+			Status:              int32(applicantsv1.ApplicantStatus_APPLICANT_STATUS_APPLIED),
+			FunFact:             util.ToNullString(funFact),
+			Availability:        util.ToNullString(availability),
+			SalaryExpectation:   util.ToNullString(salaryExpectation),
+			ScoringModelVersion: model.Version,
+			Agency:              util.ToNullString(agency),
+		})
+		if err != nil {
+			return err
 		}
-		s.logger.Error("failed to create applicant", zap.String("email", req.Email), zap.Error(err))
-		return nil, status.Errorf(codes.Internal, "failed to create applicant: %v", err)
+		return s.recordAudit(ctx, q, applicantsv1.ApplicantsService_CreateApplicant_FullMethodName, applicant.ID, nil, &applicant)
+	})
+	if err != nil {
+		return nil, s.translateError(ctx, err, "create applicant", nil)
 	}
 
-	s.logger.Info("applicant created with calculated score",
+	s.log(ctx).Info("applicant created with calculated score",
 		zap.String("name", applicant.Name),
 		zap.Float64("overall_score", overallScore),
+		zap.String("scoring_model_version", model.Version),
 		zap.Int32("status", applicant.Status),
 	)
 
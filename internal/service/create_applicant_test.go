@@ -4,29 +4,54 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"strings"
 	"testing"
 	"time"
 
 	"github.com/lib/pq"
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/auth"
 	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
-	"github.com/Thrun12/golang-assignment/internal/util"
 )
 
 // mockQuerier is a mock implementation of sqlc.Querier for testing
 type mockQuerier struct {
-	createFunc  func(ctx context.Context, params sqlc.CreateApplicantParams) (sqlc.Applicant, error)
-	getFunc     func(ctx context.Context, id int64) (sqlc.Applicant, error)
-	listFunc    func(ctx context.Context, params sqlc.ListApplicantsParams) ([]sqlc.Applicant, error)
-	countFunc   func(ctx context.Context, params sqlc.CountApplicantsParams) (int64, error)
-	updateFunc  func(ctx context.Context, params sqlc.UpdateApplicantParams) (sqlc.Applicant, error)
-	deleteFunc  func(ctx context.Context, id int64) error
-	getBestFunc func(ctx context.Context) (sqlc.Applicant, error)
+	createFunc       func(ctx context.Context, params sqlc.CreateApplicantParams) (sqlc.Applicant, error)
+	getFunc          func(ctx context.Context, id int64) (sqlc.Applicant, error)
+	listFunc         func(ctx context.Context, params sqlc.ListApplicantsParams) ([]sqlc.Applicant, error)
+	listByCursorFunc func(ctx context.Context, params sqlc.ListApplicantsByCursorParams) ([]sqlc.Applicant, error)
+	countFunc        func(ctx context.Context, params sqlc.CountApplicantsParams) (int64, error)
+	searchFunc       func(ctx context.Context, params sqlc.SearchApplicantsParams) ([]sqlc.SearchApplicantsRow, error)
+	updateFunc       func(ctx context.Context, params sqlc.UpdateApplicantParams) (sqlc.Applicant, error)
+	deleteFunc       func(ctx context.Context, id int64) (int64, error)
+	getBestFunc      func(ctx context.Context) (sqlc.Applicant, error)
+	rescoreListFunc  func(ctx context.Context, params sqlc.ListApplicantsForRescoreParams) ([]sqlc.Applicant, error)
+	previewFunc      func(ctx context.Context, params sqlc.PreviewApplicantsForRescoreParams) ([]sqlc.Applicant, error)
+	updateScoreFunc  func(ctx context.Context, params sqlc.UpdateApplicantScoreParams) (sqlc.Applicant, error)
+	execTxFunc       func(ctx context.Context, fn func(q sqlc.Querier) error) error
+	statusFunc       func(ctx context.Context, params sqlc.UpdateApplicantStatusParams) (sqlc.Applicant, error)
+	historyFunc      func(ctx context.Context, params sqlc.CreateStatusHistoryEntryParams) (sqlc.ApplicantStatusHistory, error)
+	listHistoryFunc  func(ctx context.Context, applicantID int64) ([]sqlc.ApplicantStatusHistory, error)
+	restoreFunc      func(ctx context.Context, id int64) (sqlc.Applicant, error)
+	purgeFunc        func(ctx context.Context, id int64) (int64, error)
+	createKeyFunc    func(ctx context.Context, params sqlc.CreateApiKeyParams) (sqlc.ApiKey, error)
+	getKeyFunc       func(ctx context.Context, keyHash string) (sqlc.ApiKey, error)
+	listKeysFunc     func(ctx context.Context) ([]sqlc.ApiKey, error)
+	revokeKeyFunc    func(ctx context.Context, id int64) (sqlc.ApiKey, error)
+	auditFunc        func(ctx context.Context, params sqlc.CreateAuditEventParams) (sqlc.ApplicantAuditLog, error)
+	listAuditFunc    func(ctx context.Context, params sqlc.ListAuditEventsParams) ([]sqlc.ApplicantAuditLog, error)
+}
+
+// ExecTx runs fn against the mock itself unless execTxFunc overrides it
+func (m *mockQuerier) ExecTx(ctx context.Context, fn func(q sqlc.Querier) error) error {
+	if m.execTxFunc != nil {
+		return m.execTxFunc(ctx, fn)
+	}
+	return fn(m)
 }
 
 func (m *mockQuerier) CreateApplicant(ctx context.Context, params sqlc.CreateApplicantParams) (sqlc.Applicant, error) {
@@ -43,6 +68,32 @@ func (m *mockQuerier) GetApplicant(ctx context.Context, id int64) (sqlc.Applican
 	return sqlc.Applicant{}, errors.New("getFunc not implemented")
 }
 
+// GetApplicantForUpdate behaves like GetApplicant; row locking is not modelled
+func (m *mockQuerier) GetApplicantForUpdate(ctx context.Context, id int64) (sqlc.Applicant, error) {
+	return m.GetApplicant(ctx, id)
+}
+
+func (m *mockQuerier) UpdateApplicantStatus(ctx context.Context, params sqlc.UpdateApplicantStatusParams) (sqlc.Applicant, error) {
+	if m.statusFunc != nil {
+		return m.statusFunc(ctx, params)
+	}
+	return sqlc.Applicant{}, errors.New("statusFunc not implemented")
+}
+
+func (m *mockQuerier) CreateStatusHistoryEntry(ctx context.Context, params sqlc.CreateStatusHistoryEntryParams) (sqlc.ApplicantStatusHistory, error) {
+	if m.historyFunc != nil {
+		return m.historyFunc(ctx, params)
+	}
+	return sqlc.ApplicantStatusHistory{}, errors.New("historyFunc not implemented")
+}
+
+func (m *mockQuerier) ListStatusHistory(ctx context.Context, applicantID int64) ([]sqlc.ApplicantStatusHistory, error) {
+	if m.listHistoryFunc != nil {
+		return m.listHistoryFunc(ctx, applicantID)
+	}
+	return nil, errors.New("listHistoryFunc not implemented")
+}
+
 func (m *mockQuerier) GetApplicantByEmail(ctx context.Context, email string) (sqlc.Applicant, error) {
 	return sqlc.Applicant{}, errors.New("not implemented")
 }
@@ -54,6 +105,13 @@ func (m *mockQuerier) ListApplicants(ctx context.Context, params sqlc.ListApplic
 	return nil, errors.New("listFunc not implemented")
 }
 
+func (m *mockQuerier) ListApplicantsByCursor(ctx context.Context, params sqlc.ListApplicantsByCursorParams) ([]sqlc.Applicant, error) {
+	if m.listByCursorFunc != nil {
+		return m.listByCursorFunc(ctx, params)
+	}
+	return nil, errors.New("listByCursorFunc not implemented")
+}
+
 func (m *mockQuerier) CountApplicants(ctx context.Context, params sqlc.CountApplicantsParams) (int64, error) {
 	if m.countFunc != nil {
 		return m.countFunc(ctx, params)
@@ -61,6 +119,13 @@ func (m *mockQuerier) CountApplicants(ctx context.Context, params sqlc.CountAppl
 	return 0, errors.New("countFunc not implemented")
 }
 
+func (m *mockQuerier) SearchApplicants(ctx context.Context, params sqlc.SearchApplicantsParams) ([]sqlc.SearchApplicantsRow, error) {
+	if m.searchFunc != nil {
+		return m.searchFunc(ctx, params)
+	}
+	return nil, errors.New("searchFunc not implemented")
+}
+
 func (m *mockQuerier) UpdateApplicant(ctx context.Context, params sqlc.UpdateApplicantParams) (sqlc.Applicant, error) {
 	if m.updateFunc != nil {
 		return m.updateFunc(ctx, params)
@@ -68,11 +133,33 @@ func (m *mockQuerier) UpdateApplicant(ctx context.Context, params sqlc.UpdateApp
 	return sqlc.Applicant{}, errors.New("updateFunc not implemented")
 }
 
-func (m *mockQuerier) DeleteApplicant(ctx context.Context, id int64) error {
+func (m *mockQuerier) DeleteApplicant(ctx context.Context, id int64) (int64, error) {
 	if m.deleteFunc != nil {
 		return m.deleteFunc(ctx, id)
 	}
-	return errors.New("deleteFunc not implemented")
+	return 0, errors.New("deleteFunc not implemented")
+}
+
+func (m *mockQuerier) RestoreApplicant(ctx context.Context, id int64) (sqlc.Applicant, error) {
+	if m.restoreFunc != nil {
+		return m.restoreFunc(ctx, id)
+	}
+	return sqlc.Applicant{}, errors.New("restoreFunc not implemented")
+}
+
+func (m *mockQuerier) PurgeApplicant(ctx context.Context, id int64) (int64, error) {
+	if m.purgeFunc != nil {
+		return m.purgeFunc(ctx, id)
+	}
+	return 0, errors.New("purgeFunc not implemented")
+}
+
+func (m *mockQuerier) CountPurgeableApplicants(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (m *mockQuerier) PurgeDeletedApplicants(ctx context.Context, params sqlc.PurgeDeletedApplicantsParams) (int64, error) {
+	return 0, errors.New("not implemented")
 }
 
 func (m *mockQuerier) GetTopApplicantsByPosition(ctx context.Context, params sqlc.GetTopApplicantsByPositionParams) ([]sqlc.Applicant, error) {
@@ -87,7 +174,24 @@ func (m *mockQuerier) GetBestApplicant(ctx context.Context) (sqlc.Applicant, err
 }
 
 func (m *mockQuerier) UpdateApplicantScore(ctx context.Context, params sqlc.UpdateApplicantScoreParams) (sqlc.Applicant, error) {
-	return sqlc.Applicant{}, errors.New("not implemented")
+	if m.updateScoreFunc != nil {
+		return m.updateScoreFunc(ctx, params)
+	}
+	return sqlc.Applicant{}, errors.New("updateScoreFunc not implemented")
+}
+
+func (m *mockQuerier) ListApplicantsForRescore(ctx context.Context, params sqlc.ListApplicantsForRescoreParams) ([]sqlc.Applicant, error) {
+	if m.rescoreListFunc != nil {
+		return m.rescoreListFunc(ctx, params)
+	}
+	return nil, errors.New("rescoreListFunc not implemented")
+}
+
+func (m *mockQuerier) PreviewApplicantsForRescore(ctx context.Context, params sqlc.PreviewApplicantsForRescoreParams) ([]sqlc.Applicant, error) {
+	if m.previewFunc != nil {
+		return m.previewFunc(ctx, params)
+	}
+	return nil, errors.New("previewFunc not implemented")
 }
 
 func (m *mockQuerier) DeleteAllApplicants(ctx context.Context) error {
@@ -98,201 +202,56 @@ func (m *mockQuerier) GetApplicantStats(ctx context.Context) (sqlc.GetApplicantS
 	return sqlc.GetApplicantStatsRow{}, errors.New("not implemented")
 }
 
-func TestCreateApplicantRequest_Validate(t *testing.T) {
-	tests := []struct {
-		name        string
-		input       *applicantsv1.CreateApplicantRequest
-		expectError bool
-		errorMsg    string
-	}{
-		{
-			name: "Valid input",
-			input: &applicantsv1.CreateApplicantRequest{
-				Name:             "John Doe",
-				Email:            "john@example.com",
-				Position:         "Senior Developer",
-				YearsExperience:  5,
-				GithubStars:      100,
-				InterviewScore:   85.0,
-				CulturalFitScore: 90.0,
-				TechnicalScore:   88.0,
-			},
-			expectError: false,
-		},
-		{
-			name: "Empty name",
-			input: &applicantsv1.CreateApplicantRequest{
-				Name:             "",
-				Email:            "john@example.com",
-				Position:         "Developer",
-				InterviewScore:   85.0,
-				CulturalFitScore: 90.0,
-				TechnicalScore:   88.0,
-			},
-			expectError: true,
-			errorMsg:    "name is required",
-		},
-		{
-			name: "Name too short",
-			input: &applicantsv1.CreateApplicantRequest{
-				Name:             "J",
-				Email:            "john@example.com",
-				Position:         "Developer",
-				InterviewScore:   85.0,
-				CulturalFitScore: 90.0,
-				TechnicalScore:   88.0,
-			},
-			expectError: true,
-			errorMsg:    "name must be at least 2 characters",
-		},
-		{
-			name: "Empty email",
-			input: &applicantsv1.CreateApplicantRequest{
-				Name:             "John Doe",
-				Email:            "",
-				Position:         "Developer",
-				InterviewScore:   85.0,
-				CulturalFitScore: 90.0,
-				TechnicalScore:   88.0,
-			},
-			expectError: true,
-			errorMsg:    "email is required",
-		},
-		{
-			name: "Invalid email format",
-			input: &applicantsv1.CreateApplicantRequest{
-				Name:             "John Doe",
-				Email:            "not-an-email",
-				Position:         "Developer",
-				InterviewScore:   85.0,
-				CulturalFitScore: 90.0,
-				TechnicalScore:   88.0,
-			},
-			expectError: true,
-			errorMsg:    "email must be a valid email address",
-		},
-		{
-			name: "Empty position",
-			input: &applicantsv1.CreateApplicantRequest{
-				Name:             "John Doe",
-				Email:            "john@example.com",
-				Position:         "",
-				InterviewScore:   85.0,
-				CulturalFitScore: 90.0,
-				TechnicalScore:   88.0,
-			},
-			expectError: true,
-			errorMsg:    "position is required",
-		},
-		{
-			name: "Negative years of experience",
-			input: &applicantsv1.CreateApplicantRequest{
-				Name:             "John Doe",
-				Email:            "john@example.com",
-				Position:         "Developer",
-				YearsExperience:  -5,
-				InterviewScore:   85.0,
-				CulturalFitScore: 90.0,
-				TechnicalScore:   88.0,
-			},
-			expectError: true,
-			errorMsg:    "years_experience must be positive",
-		},
-		{
-			name: "Negative github stars",
-			input: &applicantsv1.CreateApplicantRequest{
-				Name:             "John Doe",
-				Email:            "john@example.com",
-				Position:         "Developer",
-				GithubStars:      -100,
-				InterviewScore:   85.0,
-				CulturalFitScore: 90.0,
-				TechnicalScore:   88.0,
-			},
-			expectError: true,
-			errorMsg:    "github_stars must be positive",
-		},
-		{
-			name: "Interview score below 0",
-			input: &applicantsv1.CreateApplicantRequest{
-				Name:             "John Doe",
-				Email:            "john@example.com",
-				Position:         "Developer",
-				InterviewScore:   -10.0,
-				CulturalFitScore: 90.0,
-				TechnicalScore:   88.0,
-			},
-			expectError: true,
-			errorMsg:    "interview_score must be between 0 and 100",
-		},
-		{
-			name: "Interview score above 100",
-			input: &applicantsv1.CreateApplicantRequest{
-				Name:             "John Doe",
-				Email:            "john@example.com",
-				Position:         "Developer",
-				InterviewScore:   110.0,
-				CulturalFitScore: 90.0,
-				TechnicalScore:   88.0,
-			},
-			expectError: true,
-			errorMsg:    "interview_score must be between 0 and 100",
-		},
-		{
-			name: "Cultural fit score out of range",
-			input: &applicantsv1.CreateApplicantRequest{
-				Name:             "John Doe",
-				Email:            "john@example.com",
-				Position:         "Developer",
-				InterviewScore:   85.0,
-				CulturalFitScore: 150.0,
-				TechnicalScore:   88.0,
-			},
-			expectError: true,
-			errorMsg:    "cultural_fit_score must be between 0 and 100",
-		},
-		{
-			name: "Technical score out of range",
-			input: &applicantsv1.CreateApplicantRequest{
-				Name:             "John Doe",
-				Email:            "john@example.com",
-				Position:         "Developer",
-				InterviewScore:   85.0,
-				CulturalFitScore: 90.0,
-				TechnicalScore:   -5.0,
-			},
-			expectError: true,
-			errorMsg:    "technical_score must be between 0 and 100",
-		},
+func (m *mockQuerier) CountApplicantsByStatus(ctx context.Context) ([]sqlc.CountApplicantsByStatusRow, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockQuerier) CreateApiKey(ctx context.Context, params sqlc.CreateApiKeyParams) (sqlc.ApiKey, error) {
+	if m.createKeyFunc != nil {
+		return m.createKeyFunc(ctx, params)
 	}
+	return sqlc.ApiKey{}, errors.New("createKeyFunc not implemented")
+}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			err := util.ValidateApplicant(
-				tt.input.Name,
-				tt.input.Email,
-				tt.input.Position,
-				tt.input.YearsExperience,
-				tt.input.GithubStars,
-				tt.input.InterviewScore,
-				tt.input.CulturalFitScore,
-				tt.input.TechnicalScore,
-				false, // not an update
-				0,     // id not needed for create
-			)
-			if tt.expectError {
-				if err == nil {
-					t.Errorf("Expected error but got nil")
-				} else if tt.errorMsg != "" && err.Error() != tt.errorMsg {
-					t.Errorf("Expected error message '%s', got '%s'", tt.errorMsg, err.Error())
-				}
-			} else {
-				if err != nil {
-					t.Errorf("Expected no error but got: %v", err)
-				}
-			}
-		})
+func (m *mockQuerier) GetActiveApiKeyByHash(ctx context.Context, keyHash string) (sqlc.ApiKey, error) {
+	if m.getKeyFunc != nil {
+		return m.getKeyFunc(ctx, keyHash)
+	}
+	return sqlc.ApiKey{}, errors.New("getKeyFunc not implemented")
+}
+
+func (m *mockQuerier) ListApiKeys(ctx context.Context) ([]sqlc.ApiKey, error) {
+	if m.listKeysFunc != nil {
+		return m.listKeysFunc(ctx)
+	}
+	return nil, errors.New("listKeysFunc not implemented")
+}
+
+func (m *mockQuerier) RevokeApiKey(ctx context.Context, id int64) (sqlc.ApiKey, error) {
+	if m.revokeKeyFunc != nil {
+		return m.revokeKeyFunc(ctx, id)
+	}
+	return sqlc.ApiKey{}, errors.New("revokeKeyFunc not implemented")
+}
+
+func (m *mockQuerier) TouchApiKey(ctx context.Context, id int64) error {
+	return nil
+}
+
+// CreateAuditEvent succeeds unless auditFunc overrides it, so tests of
+// mutations need not care about the audit log
+func (m *mockQuerier) CreateAuditEvent(ctx context.Context, params sqlc.CreateAuditEventParams) (sqlc.ApplicantAuditLog, error) {
+	if m.auditFunc != nil {
+		return m.auditFunc(ctx, params)
 	}
+	return sqlc.ApplicantAuditLog{ApplicantID: params.ApplicantID, Actor: params.Actor, Method: params.Method}, nil
+}
+
+func (m *mockQuerier) ListAuditEvents(ctx context.Context, params sqlc.ListAuditEventsParams) ([]sqlc.ApplicantAuditLog, error) {
+	if m.listAuditFunc != nil {
+		return m.listAuditFunc(ctx, params)
+	}
+	return nil, errors.New("listAuditFunc not implemented")
 }
 
 func TestCreateApplicant(t *testing.T) {
@@ -373,30 +332,6 @@ func TestCreateApplicant(t *testing.T) {
 		}
 	})
 
-	t.Run("Validation failure", func(t *testing.T) {
-		service := &ApplicantService{
-			queries: &mockQuerier{},
-			logger:  logger,
-		}
-
-		req := &applicantsv1.CreateApplicantRequest{
-			Name:  "", // Invalid: empty name
-			Email: "jane@example.com",
-		}
-
-		resp, err := service.CreateApplicant(ctx, req)
-		if err == nil {
-			t.Fatal("Expected validation error, got nil")
-		}
-		st, ok := status.FromError(err)
-		if !ok || st.Code() != codes.InvalidArgument {
-			t.Error("Expected InvalidArgument status code")
-		}
-		if resp != nil {
-			t.Error("Expected nil response on validation error")
-		}
-	})
-
 	t.Run("Repository error", func(t *testing.T) {
 		mockQ := &mockQuerier{
 			createFunc: func(ctx context.Context, params sqlc.CreateApplicantParams) (sqlc.Applicant, error) {
@@ -472,33 +407,6 @@ func TestCreateApplicant(t *testing.T) {
 		}
 	})
 
-	t.Run("Validation - name too long", func(t *testing.T) {
-		service := &ApplicantService{
-			queries: &mockQuerier{},
-			logger:  logger,
-		}
-
-		// Name with more than 255 characters
-		longName := strings.Repeat("a", 256)
-
-		req := &applicantsv1.CreateApplicantRequest{
-			Name:             longName,
-			Email:            "jane@example.com",
-			Position:         "Developer",
-			InterviewScore:   85.0,
-			CulturalFitScore: 90.0,
-			TechnicalScore:   88.0,
-		}
-
-		resp, err := service.CreateApplicant(ctx, req)
-		if err == nil {
-			t.Fatal("Expected validation error, got nil")
-		}
-		if resp != nil {
-			t.Error("Expected nil response on validation error")
-		}
-	})
-
 	t.Run("With optional fields populated", func(t *testing.T) {
 		mockQ := &mockQuerier{
 			createFunc: func(ctx context.Context, params sqlc.CreateApplicantParams) (sqlc.Applicant, error) {
@@ -552,4 +460,86 @@ func TestCreateApplicant(t *testing.T) {
 			t.Errorf("Expected fun fact to be preserved")
 		}
 	})
+
+	t.Run("Agency callers own their applicants", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			createFunc: func(ctx context.Context, params sqlc.CreateApplicantParams) (sqlc.Applicant, error) {
+				if !params.Agency.Valid || params.Agency.String != "talent-co" {
+					t.Errorf("Expected agency 'talent-co', got %+v", params.Agency)
+				}
+				return sqlc.Applicant{ID: 1, Name: params.Name, Email: params.Email, Agency: params.Agency}, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		agencyCtx := auth.NewContext(ctx, &auth.Principal{Subject: "sourcer@talent.co", Roles: []string{"agency"}, Agency: "talent-co"})
+		resp, err := service.CreateApplicant(agencyCtx, &applicantsv1.CreateApplicantRequest{
+			Name:     "Jane Doe",
+			Email:    "jane@example.com",
+			Position: "Developer",
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if resp.Applicant.Agency != "talent-co" {
+			t.Errorf("Expected agency 'talent-co', got '%s'", resp.Applicant.Agency)
+		}
+	})
+	t.Run("Unset status starts as applied", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			createFunc: func(ctx context.Context, params sqlc.CreateApplicantParams) (sqlc.Applicant, error) {
+				return sqlc.Applicant{ID: 1, Name: params.Name, Email: params.Email, Status: params.Status}, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.CreateApplicant(ctx, &applicantsv1.CreateApplicantRequest{
+			Name:     "Jane Doe",
+			Email:    "jane@example.com",
+			Position: "Developer",
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if resp.Applicant.Status != applicantsv1.ApplicantStatus_APPLICANT_STATUS_APPLIED {
+			t.Errorf("Expected status APPLIED, got %s", resp.Applicant.Status)
+		}
+	})
+
+	for _, initial := range []applicantsv1.ApplicantStatus{
+		applicantsv1.ApplicantStatus_APPLICANT_STATUS_HIRED,
+		applicantsv1.ApplicantStatus_APPLICANT_STATUS_OBVIOUSLY_THE_BEST,
+		applicantsv1.ApplicantStatus(99),
+	} {
+		t.Run("Rejects initial status "+initial.String(), func(t *testing.T) {
+			service := &ApplicantService{
+				queries: &mockQuerier{},
+				logger:  logger,
+			}
+
+			_, err := service.CreateApplicant(ctx, &applicantsv1.CreateApplicantRequest{
+				Name:     "Jane Doe",
+				Email:    "jane@example.com",
+				Position: "Developer",
+				Status:   initial,
+			})
+			st, _ := status.FromError(err)
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("Expected InvalidArgument, got %v", err)
+			}
+			for _, detail := range st.Details() {
+				if badRequest, ok := detail.(*errdetails.BadRequest); ok && badRequest.GetFieldViolations()[0].GetField() != "status" {
+					t.Errorf("Expected a violation on status, got %v", badRequest.GetFieldViolations())
+				}
+			}
+		})
+	}
 }
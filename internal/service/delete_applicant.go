@@ -2,27 +2,52 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"go.uber.org/zap"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
 	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
 )
 
-// DeleteApplicant deletes an applicant by ID
+// DeleteApplicant deletes an applicant by ID. The request must carry the
+// applicant's current etag.
 func (s *ApplicantService) DeleteApplicant(ctx context.Context, req *applicantsv1.DeleteApplicantRequest) (*applicantsv1.DeleteApplicantResponse, error) {
 	// Validate input
 	if req.Id <= 0 {
-		return nil, status.Errorf(codes.InvalidArgument, "id must be positive")
+		return nil, invalidField("id", "id must be positive")
 	}
+	etag := requestETag(ctx, req.Etag)
+	if err := requireETag(etag); err != nil {
+		return nil, err
+	}
+
+	s.log(ctx).Debug("deleting applicant", zap.Int64("id", req.Id))
+
+	err := s.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		existing, err := q.GetApplicantForUpdate(ctx, req.Id)
+		if err != nil {
+			return err
+		}
+		if err := checkETag(&existing, etag); err != nil {
+			return err
+		}
 
-	s.logger.Debug("deleting applicant", zap.Int64("id", req.Id))
+		rows, err := q.DeleteApplicant(ctx, req.Id)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sql.ErrNoRows
+		}
 
-	err := s.queries.DeleteApplicant(ctx, req.Id)
+		deleted := existing
+		deleted.DeletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		return s.recordAudit(ctx, q, applicantsv1.ApplicantsService_DeleteApplicant_FullMethodName, req.Id, &existing, &deleted)
+	})
 	if err != nil {
-		s.logger.Error("failed to delete applicant", zap.Int64("id", req.Id), zap.Error(err))
-		return nil, status.Errorf(codes.Internal, "failed to delete applicant: %v", err)
+		return nil, s.translateError(ctx, err, "delete applicant", applicantResource(req.Id))
 	}
 
 	return &applicantsv1.DeleteApplicantResponse{
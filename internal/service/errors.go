@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+
+	"github.com/Thrun12/golang-assignment/internal/util"
+)
+
+// constraintFields maps database constraint and unique index names to the
+// request fields they guard, for BadRequest field violations
+var constraintFields = map[string]string{
+	"applicants_email_key":        "email",
+	"idx_applicants_email_active": "email",
+	"interview_score_range":       "interview_score",
+	"cultural_fit_score_range":    "cultural_fit_score",
+	"technical_score_range":       "technical_score",
+	"overall_score_range":         "overall_score",
+	"years_experience_positive":   "years_experience",
+	"github_stars_positive":       "github_stars",
+}
+
+// applicantResource identifies an applicant in error details
+func applicantResource(id int64) *errdetails.ResourceInfo {
+	return &errdetails.ResourceInfo{
+		ResourceType: "applicant",
+		ResourceName: strconv.FormatInt(id, 10),
+	}
+}
+
+// translateError converts an error returned while performing op into a gRPC
+// status error with structured details. Errors that already carry a status are
+// returned unchanged. resource identifies the entity op acted on and may be nil.
+func (s *ApplicantService) translateError(ctx context.Context, err error, op string, resource *errdetails.ResourceInfo) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var pqErr *pq.Error
+	var verr *util.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		return invalidRequest(verr)
+
+	case errors.Is(err, sql.ErrNoRows):
+		msg := "not found"
+		if resource != nil {
+			msg = fmt.Sprintf("%s not found: %s", resource.ResourceType, resource.ResourceName)
+			resource.Description = msg
+		}
+		return withDetails(codes.NotFound, msg, resource)
+
+	case errors.Is(err, errInvalidStatusTransition):
+		violation := &errdetails.PreconditionFailure_Violation{
+			Type:        "STATUS_TRANSITION",
+			Description: err.Error(),
+		}
+		if resource != nil {
+			violation.Subject = resource.ResourceType + "/" + resource.ResourceName
+		}
+		return withDetails(codes.FailedPrecondition, err.Error(), &errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{violation},
+		})
+
+	case errors.Is(err, errETagMismatch):
+		violation := &errdetails.PreconditionFailure_Violation{
+			Type:        util.ETagPreconditionType,
+			Description: err.Error(),
+		}
+		if resource != nil {
+			violation.Subject = resource.ResourceType + "/" + resource.ResourceName
+		}
+		return withDetails(codes.Aborted, err.Error(), &errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{violation},
+		})
+
+	case errors.Is(err, context.Canceled):
+		return status.Errorf(codes.Canceled, "failed to %s: request canceled", op)
+
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Errorf(codes.DeadlineExceeded, "failed to %s: deadline exceeded", op)
+
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone):
+		s.log(ctx).Warn("database unavailable", zap.String("op", op), zap.Error(err))
+		return status.Errorf(codes.Unavailable, "failed to %s: database unavailable", op)
+
+	case errors.As(err, &pqErr):
+		return s.translatePQError(ctx, pqErr, op, resource)
+	}
+
+	s.log(ctx).Error("operation failed", zap.String("op", op), zap.Error(err))
+	return status.Errorf(codes.Internal, "failed to %s", op)
+}
+
+// translatePQError maps PostgreSQL error codes to gRPC codes
+func (s *ApplicantService) translatePQError(ctx context.Context, pqErr *pq.Error, op string, resource *errdetails.ResourceInfo) error {
+	field := constraintFields[pqErr.Constraint]
+
+	switch pqErr.Code {
+	case "23505": // unique_violation
+		msg := "resource already exists"
+		if field != "" {
+			msg = field + " already exists"
+		}
+		if resource != nil {
+			resource.Description = msg
+		}
+		return withDetails(codes.AlreadyExists, msg, fieldViolation(field, msg), resource)
+
+	case "23514": // check_violation
+		msg := "value out of range"
+		if field != "" {
+			msg = field + " is out of range"
+		}
+		return withDetails(codes.InvalidArgument, msg, fieldViolation(field, msg))
+
+	case "23503": // foreign_key_violation
+		return withDetails(codes.FailedPrecondition, "referenced resource does not exist", resource)
+
+	case "57014": // query_canceled, raised by statement_timeout
+		return status.Errorf(codes.DeadlineExceeded, "failed to %s: query timed out", op)
+
+	case "40001", "40P01", "55P03": // serialization_failure, deadlock_detected, lock_not_available
+		return status.Errorf(codes.Aborted, "failed to %s: concurrent modification, retry the request", op)
+	}
+
+	if pqErr.Code.Class() == "08" { // connection_exception
+		s.log(ctx).Warn("database unavailable", zap.String("op", op), zap.Error(pqErr))
+		return status.Errorf(codes.Unavailable, "failed to %s: database unavailable", op)
+	}
+
+	s.log(ctx).Error("database error",
+		zap.String("op", op),
+		zap.String("code", string(pqErr.Code)),
+		zap.Error(pqErr),
+	)
+	return status.Errorf(codes.Internal, "failed to %s", op)
+}
+
+// invalidRequest converts a validation error into an InvalidArgument status
+// carrying every field violation as a BadRequest detail
+func invalidRequest(err error) error {
+	var verr *util.ValidationError
+	if !errors.As(err, &verr) {
+		return status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range verr.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	return withDetails(codes.InvalidArgument, "validation failed: "+verr.Error(), badRequest)
+}
+
+// invalidField reports a single invalid request field
+func invalidField(field, description string) error {
+	return invalidRequest(util.NewFieldError(field, description))
+}
+
+// fieldViolation returns a BadRequest detail for field, or nil if field is unknown
+func fieldViolation(field, description string) *errdetails.BadRequest {
+	if field == "" {
+		return nil
+	}
+	return &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: field, Description: description},
+		},
+	}
+}
+
+// withDetails builds a status error carrying the non-nil details
+func withDetails(code codes.Code, msg string, details ...proto.Message) error {
+	st := status.New(code, msg)
+	for _, d := range details {
+		if d == nil || !d.ProtoReflect().IsValid() {
+			continue
+		}
+		if withDetail, err := st.WithDetails(protoadapt.MessageV1Of(d)); err == nil {
+			st = withDetail
+		}
+	}
+	return st.Err()
+}
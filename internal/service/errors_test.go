@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTranslateError(t *testing.T) {
+	service := &ApplicantService{logger: zap.NewNop()}
+
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode codes.Code
+		expectField  string
+		expectInfo   bool
+	}{
+		{
+			name:         "No rows",
+			err:          sql.ErrNoRows,
+			expectedCode: codes.NotFound,
+			expectInfo:   true,
+		},
+		{
+			name:         "Wrapped no rows",
+			err:          fmt.Errorf("query failed: %w", sql.ErrNoRows),
+			expectedCode: codes.NotFound,
+			expectInfo:   true,
+		},
+		{
+			name:         "Unique violation on email",
+			err:          &pq.Error{Code: "23505", Constraint: "idx_applicants_email_active"},
+			expectedCode: codes.AlreadyExists,
+			expectField:  "email",
+			expectInfo:   true,
+		},
+		{
+			name:         "Check violation",
+			err:          &pq.Error{Code: "23514", Constraint: "interview_score_range"},
+			expectedCode: codes.InvalidArgument,
+			expectField:  "interview_score",
+		},
+		{
+			name:         "Statement timeout",
+			err:          &pq.Error{Code: "57014"},
+			expectedCode: codes.DeadlineExceeded,
+		},
+		{
+			name:         "Deadlock",
+			err:          &pq.Error{Code: "40P01"},
+			expectedCode: codes.Aborted,
+		},
+		{
+			name:         "Connection failure",
+			err:          &pq.Error{Code: "08006"},
+			expectedCode: codes.Unavailable,
+		},
+		{
+			name:         "Context deadline",
+			err:          context.DeadlineExceeded,
+			expectedCode: codes.DeadlineExceeded,
+		},
+		{
+			name:         "Context canceled",
+			err:          context.Canceled,
+			expectedCode: codes.Canceled,
+		},
+		{
+			name:         "Invalid status transition",
+			err:          fmt.Errorf("%w from A to B", errInvalidStatusTransition),
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name:         "Existing status error is kept",
+			err:          status.Error(codes.PermissionDenied, "nope"),
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "Unknown error",
+			err:          errors.New("connection reset by peer"),
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.translateError(context.Background(), tt.err, "get applicant", applicantResource(7))
+			st := status.Convert(err)
+			if st.Code() != tt.expectedCode {
+				t.Fatalf("Expected %v, got %v (%s)", tt.expectedCode, st.Code(), st.Message())
+			}
+
+			var field string
+			var info *errdetails.ResourceInfo
+			for _, d := range st.Details() {
+				switch detail := d.(type) {
+				case *errdetails.BadRequest:
+					field = detail.FieldViolations[0].Field
+				case *errdetails.ResourceInfo:
+					info = detail
+				}
+			}
+			if field != tt.expectField {
+				t.Errorf("Expected field violation %q, got %q", tt.expectField, field)
+			}
+			if tt.expectInfo && (info == nil || info.ResourceName != "7") {
+				t.Errorf("Expected resource info for applicant 7, got %v", info)
+			}
+		})
+	}
+
+	t.Run("Internal errors do not leak details", func(t *testing.T) {
+		err := service.translateError(context.Background(), errors.New("password authentication failed"), "list applicants", nil)
+		if st := status.Convert(err); st.Message() != "failed to list applicants" {
+			t.Errorf("Expected generic message, got %q", st.Message())
+		}
+	})
+}
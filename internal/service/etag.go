@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+	"github.com/Thrun12/golang-assignment/internal/util"
+)
+
+// ifMatchMetadataKey is the metadata key the gateway forwards the If-Match header as
+const ifMatchMetadataKey = "if-match"
+
+// errETagMismatch is returned when a request's etag does not match the stored applicant
+var errETagMismatch = errors.New("applicant was modified since it was read, fetch it again and retry")
+
+// requestETag returns the etag sent in the request body, falling back to the
+// If-Match header forwarded by the gateway
+func requestETag(ctx context.Context, etag string) string {
+	if etag != "" {
+		return etag
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		return strings.Join(md.Get(ifMatchMetadataKey), ",")
+	}
+	return ""
+}
+
+// requireETag returns an InvalidArgument error if no etag was sent
+func requireETag(etag string) error {
+	if etag == "" {
+		return invalidField("etag", "etag is required, send the applicant's current etag or an If-Match header")
+	}
+	return nil
+}
+
+// checkETag verifies that etag matches the applicant's current version
+func checkETag(applicant *sqlc.Applicant, etag string) error {
+	if !util.ETagMatches(etag, util.FormatETag(applicant.Version)) {
+		return errETagMismatch
+	}
+	return nil
+}
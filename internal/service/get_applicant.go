@@ -4,8 +4,6 @@ import (
 	"context"
 
 	"go.uber.org/zap"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
 	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
 	"github.com/Thrun12/golang-assignment/internal/util"
@@ -15,18 +13,23 @@ import (
 func (s *ApplicantService) GetApplicant(ctx context.Context, req *applicantsv1.GetApplicantRequest) (*applicantsv1.GetApplicantResponse, error) {
 	// Validate input
 	if req.Id <= 0 {
-		return nil, status.Errorf(codes.InvalidArgument, "id must be positive")
+		return nil, invalidField("id", "id must be positive")
 	}
 
-	s.logger.Debug("getting applicant", zap.Int64("id", req.Id))
+	s.log(ctx).Debug("getting applicant", zap.Int64("id", req.Id))
 
 	applicant, err := s.queries.GetApplicant(ctx, req.Id)
 	if err != nil {
-		s.logger.Error("failed to get applicant", zap.Int64("id", req.Id), zap.Error(err))
-		return nil, status.Errorf(codes.NotFound, "applicant not found: %v", err)
+		return nil, s.translateError(ctx, err, "get applicant", applicantResource(req.Id))
+	}
+
+	protoApplicant := util.DbApplicantToProto(&applicant)
+	if req.IncludeScoreBreakdown {
+		breakdown := s.explainScore(&applicant)
+		protoApplicant.ScoreBreakdown = util.ScoreBreakdownToProto(&breakdown)
 	}
 
 	return &applicantsv1.GetApplicantResponse{
-		Applicant: util.DbApplicantToProto(&applicant),
+		Applicant: protoApplicant,
 	}, nil
 }
@@ -5,9 +5,7 @@ import (
 	"fmt"
 	"strings"
 
-	"go.uber.org/zap"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 
 	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
 	"github.com/Thrun12/golang-assignment/internal/util"
@@ -15,12 +13,14 @@ import (
 
 // GetBestApplicant retrieves the best applicant (spoiler: it's Jonathan)
 func (s *ApplicantService) GetBestApplicant(ctx context.Context, req *applicantsv1.GetBestApplicantRequest) (*applicantsv1.GetBestApplicantResponse, error) {
-	s.logger.Debug("getting best applicant")
+	s.log(ctx).Debug("getting best applicant")
 
 	applicant, err := s.queries.GetBestApplicant(ctx)
 	if err != nil {
-		s.logger.Error("failed to get best applicant", zap.Error(err))
-		return nil, status.Errorf(codes.Internal, "failed to get best applicant: %v", err)
+		return nil, s.translateError(ctx, err, "get best applicant", &errdetails.ResourceInfo{
+			ResourceType: "applicant",
+			ResourceName: "best",
+		})
 	}
 
 	// Generate reason why they're the best
@@ -2,22 +2,25 @@ package service
 
 import (
 	"context"
+	"fmt"
 
 	"go.uber.org/zap"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
 	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
 	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
 	"github.com/Thrun12/golang-assignment/internal/util"
 )
 
-// ListApplicants retrieves a list of applicants with pagination
+// ListApplicants retrieves a list of applicants with pagination.
+// A page_token selects keyset pagination; otherwise limit/offset is used.
 func (s *ApplicantService) ListApplicants(ctx context.Context, req *applicantsv1.ListApplicantsRequest) (*applicantsv1.ListApplicantsResponse, error) {
-	s.logger.Debug("listing applicants",
+	s.log(ctx).Debug("listing applicants",
 		zap.Int32("limit", req.Limit),
 		zap.Int32("offset", req.Offset),
 		zap.String("position", req.Position),
+		zap.Bool("page_token", req.PageToken != ""),
+		zap.Strings("skills_any", req.SkillsAny),
+		zap.Strings("skills_all", req.SkillsAll),
 	)
 
 	// Default limit
@@ -35,40 +38,104 @@ func (s *ApplicantService) ListApplicants(ctx context.Context, req *applicantsv1
 		offset = 0
 	}
 
+	// Normalize skill filters to match the lower-cased skills column
+	skillsAny := util.NormalizeSkills(req.SkillsAny)
+	skillsAll := util.NormalizeSkills(req.SkillsAll)
+	filters := util.ListFilters{
+		Position:  req.Position,
+		Status:    int32(req.Status),
+		MinScore:  req.MinScore,
+		SkillsAny: skillsAny,
+		SkillsAll: skillsAll,
+	}
+
 	// Get applicants
-	applicants, err := s.queries.ListApplicants(ctx, sqlc.ListApplicantsParams{
-		Limit:    limit,
-		Offset:   offset,
-		Position: req.Position,
-		Status:   int32(req.Status),
-		MinScore: req.MinScore,
-	})
-	if err != nil {
-		s.logger.Error("failed to list applicants", zap.Error(err))
-		return nil, status.Errorf(codes.Internal, "failed to list applicants: %v", err)
+	var applicants []sqlc.Applicant
+	var hasMore bool
+	if req.PageToken != "" {
+		cursor, err := util.DecodePageToken(req.PageToken, filters)
+		if err != nil {
+			return nil, invalidField("page_token", fmt.Sprintf("page_token is invalid: %v", err))
+		}
+		offset = 0
+
+		// Fetch one extra row to find out whether another page follows
+		applicants, err = s.queries.ListApplicantsByCursor(ctx, sqlc.ListApplicantsByCursorParams{
+			Position:        req.Position,
+			Status:          int32(req.Status),
+			MinScore:        req.MinScore,
+			SkillsAny:       skillsAny,
+			SkillsAll:       skillsAll,
+			CursorScore:     cursor.OverallScore,
+			CursorCreatedAt: cursor.CreatedAt,
+			CursorID:        cursor.ID,
+			PageSize:        limit + 1,
+		})
+		if err != nil {
+			return nil, s.translateError(ctx, err, "list applicants", nil)
+		}
+		if len(applicants) > int(limit) {
+			applicants = applicants[:limit]
+			hasMore = true
+		}
+	} else {
+		var err error
+		applicants, err = s.queries.ListApplicants(ctx, sqlc.ListApplicantsParams{
+			Limit:     limit,
+			Offset:    offset,
+			Position:  req.Position,
+			Status:    int32(req.Status),
+			MinScore:  req.MinScore,
+			SkillsAny: skillsAny,
+			SkillsAll: skillsAll,
+		})
+		if err != nil {
+			return nil, s.translateError(ctx, err, "list applicants", nil)
+		}
 	}
 
 	// Get total count
 	totalCount, err := s.queries.CountApplicants(ctx, sqlc.CountApplicantsParams{
-		Position: req.Position,
-		Status:   int32(req.Status),
-		MinScore: req.MinScore,
+		Position:  req.Position,
+		Status:    int32(req.Status),
+		MinScore:  req.MinScore,
+		SkillsAny: skillsAny,
+		SkillsAll: skillsAll,
 	})
 	if err != nil {
-		s.logger.Error("failed to count applicants", zap.Error(err))
-		return nil, status.Errorf(codes.Internal, "failed to count applicants: %v", err)
+		return nil, s.translateError(ctx, err, "count applicants", nil)
+	}
+
+	if req.PageToken == "" {
+		hasMore = int64(offset)+int64(len(applicants)) < totalCount
 	}
 
 	// Convert to proto
 	protoApplicants := make([]*applicantsv1.JobApplicant, len(applicants))
 	for i, app := range applicants {
 		protoApplicants[i] = util.DbApplicantToProto(&app)
+		if req.IncludeScoreBreakdown {
+			breakdown := s.explainScore(&app)
+			protoApplicants[i].ScoreBreakdown = util.ScoreBreakdownToProto(&breakdown)
+		}
+	}
+
+	// Build cursor from the last applicant on this page
+	var nextPageToken string
+	if hasMore && len(applicants) > 0 {
+		last := applicants[len(applicants)-1]
+		nextPageToken = util.EncodePageToken(util.PageCursor{
+			OverallScore: last.OverallScore,
+			CreatedAt:    last.CreatedAt,
+			ID:           last.ID,
+		}, filters)
 	}
 
 	return &applicantsv1.ListApplicantsResponse{
-		Applicants: protoApplicants,
-		TotalCount: int32(totalCount),
-		Limit:      limit,
-		Offset:     offset,
+		Applicants:    protoApplicants,
+		TotalCount:    int32(totalCount),
+		Limit:         limit,
+		Offset:        offset,
+		NextPageToken: nextPageToken,
 	}, nil
 }
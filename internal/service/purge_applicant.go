@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+)
+
+// PurgeApplicant permanently deletes a soft-deleted applicant
+func (s *ApplicantService) PurgeApplicant(ctx context.Context, req *applicantsv1.PurgeApplicantRequest) (*applicantsv1.PurgeApplicantResponse, error) {
+	// Validate input
+	if req.Id <= 0 {
+		return nil, invalidField("id", "id must be positive")
+	}
+
+	s.log(ctx).Debug("purging applicant", zap.Int64("id", req.Id))
+
+	// The audit event records that the applicant was purged, but none of its data
+	var rows int64
+	err := s.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		var err error
+		rows, err = q.PurgeApplicant(ctx, req.Id)
+		if err != nil || rows == 0 {
+			return err
+		}
+		return s.recordAudit(ctx, q, applicantsv1.ApplicantsService_PurgeApplicant_FullMethodName, req.Id, nil, nil)
+	})
+	if err != nil {
+		return nil, s.translateError(ctx, err, "purge applicant", applicantResource(req.Id))
+	}
+
+	if rows == 0 {
+		// Distinguish an active applicant from one that does not exist
+		if _, err := s.queries.GetApplicant(ctx, req.Id); err != nil {
+			return nil, s.translateError(ctx, err, "purge applicant", applicantResource(req.Id))
+		}
+		return nil, withDetails(codes.FailedPrecondition,
+			fmt.Sprintf("applicant %d must be deleted before it can be purged", req.Id),
+			&errdetails.PreconditionFailure{
+				Violations: []*errdetails.PreconditionFailure_Violation{{
+					Type:        "NOT_DELETED",
+					Subject:     fmt.Sprintf("applicant/%d", req.Id),
+					Description: "only deleted applicants can be purged",
+				}},
+			},
+		)
+	}
+
+	s.log(ctx).Info("purged applicant", zap.Int64("id", req.Id))
+
+	return &applicantsv1.PurgeApplicantResponse{
+		Success: true,
+	}, nil
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+	"github.com/Thrun12/golang-assignment/internal/util"
+)
+
+// maxReportedScoreChanges caps the changes listed in a RecomputeScoresResponse
+// so a run over a large table stays well below the message size limit
+const maxReportedScoreChanges = 1000
+
+// RecomputeScores recalculates stored overall scores with the current scoring
+// models. Applicants are processed in ID order, one transaction per batch, so an
+// interrupted run can be resumed from the returned last_id.
+func (s *ApplicantService) RecomputeScores(ctx context.Context, req *applicantsv1.RecomputeScoresRequest) (*applicantsv1.RecomputeScoresResponse, error) {
+	// Validate input
+	verr := &util.ValidationError{}
+	if req.AfterId < 0 {
+		verr.Add("after_id", "after_id must not be negative")
+	}
+	if req.MaxApplicants < 0 {
+		verr.Add("max_applicants", "max_applicants must not be negative")
+	}
+	if err := verr.Err(); err != nil {
+		return nil, invalidRequest(err)
+	}
+
+	// Default batch size
+	batchSize := req.BatchSize
+	if batchSize < 1 {
+		batchSize = 100
+	}
+	if batchSize > 1000 {
+		batchSize = 1000
+	}
+
+	s.log(ctx).Info("recomputing applicant scores",
+		zap.Bool("dry_run", req.DryRun),
+		zap.Int32("batch_size", batchSize),
+		zap.Int64("after_id", req.AfterId),
+		zap.Int32("max_applicants", req.MaxApplicants),
+	)
+
+	resp := &applicantsv1.RecomputeScoresResponse{
+		LastId: req.AfterId,
+		DryRun: req.DryRun,
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, s.translateError(ctx, err, "recompute scores", nil)
+		}
+
+		size := batchSize
+		if req.MaxApplicants > 0 {
+			remaining := req.MaxApplicants - resp.Scanned
+			if remaining <= 0 {
+				break
+			}
+			size = min(size, remaining)
+		}
+
+		var scanned int32
+		var lastID int64
+		var changes []*applicantsv1.ScoreChange
+		err := s.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+			var applicants []sqlc.Applicant
+			var err error
+			if req.DryRun {
+				// A dry run only reports, so it does not lock the batch against
+				// concurrent updates
+				applicants, err = q.PreviewApplicantsForRescore(ctx, sqlc.PreviewApplicantsForRescoreParams{
+					AfterID:   resp.LastId,
+					BatchSize: size,
+				})
+			} else {
+				applicants, err = q.ListApplicantsForRescore(ctx, sqlc.ListApplicantsForRescoreParams{
+					AfterID:   resp.LastId,
+					BatchSize: size,
+				})
+			}
+			if err != nil {
+				return err
+			}
+
+			scanned = int32(len(applicants))
+			for i := range applicants {
+				change := s.rescore(&applicants[i])
+				if change == nil {
+					continue
+				}
+				changes = append(changes, change)
+
+				if req.DryRun {
+					continue
+				}
+				updated, err := q.UpdateApplicantScore(ctx, sqlc.UpdateApplicantScoreParams{
+					ID:                  change.ApplicantId,
+					OverallScore:        change.NewScore,
+					ScoringModelVersion: change.NewScoringModelVersion,
+				})
+				if err != nil {
+					return err
+				}
+				if err := s.recordAudit(ctx, q, applicantsv1.ApplicantsService_RecomputeScores_FullMethodName, change.ApplicantId, &applicants[i], &updated); err != nil {
+					return err
+				}
+			}
+			if scanned > 0 {
+				lastID = applicants[scanned-1].ID
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, s.translateError(ctx, err, fmt.Sprintf("recompute scores after id %d", resp.LastId), nil)
+		}
+
+		if scanned > 0 {
+			resp.LastId = lastID
+		}
+		resp.Scanned += scanned
+		resp.Changed += int32(len(changes))
+		if room := maxReportedScoreChanges - len(resp.Changes); len(changes) > room {
+			changes = changes[:room]
+			resp.ChangesTruncated = true
+		}
+		resp.Changes = append(resp.Changes, changes...)
+
+		if scanned < size {
+			resp.Done = true
+			break
+		}
+	}
+
+	s.log(ctx).Info("recomputed applicant scores",
+		zap.Bool("dry_run", req.DryRun),
+		zap.Int32("scanned", resp.Scanned),
+		zap.Int32("changed", resp.Changed),
+		zap.Int64("last_id", resp.LastId),
+		zap.Bool("done", resp.Done),
+	)
+
+	return resp, nil
+}
+
+// rescore scores an applicant with the current model for its position and
+// returns the resulting change, or nil if the stored score is up to date
+func (s *ApplicantService) rescore(app *sqlc.Applicant) *applicantsv1.ScoreChange {
+	model := s.scoringModels.ForPosition(app.Position)
+	newScore := model.Score(scoringInput(app))
+
+	if newScore == app.OverallScore && model.Version == app.ScoringModelVersion {
+		return nil
+	}
+
+	return &applicantsv1.ScoreChange{
+		ApplicantId:            app.ID,
+		Name:                   app.Name,
+		OldScore:               app.OverallScore,
+		NewScore:               newScore,
+		OldScoringModelVersion: app.ScoringModelVersion,
+		NewScoringModelVersion: model.Version,
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"go.uber.org/zap"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+	"github.com/Thrun12/golang-assignment/internal/util"
+)
+
+// RestoreApplicant restores a soft-deleted applicant
+func (s *ApplicantService) RestoreApplicant(ctx context.Context, req *applicantsv1.RestoreApplicantRequest) (*applicantsv1.RestoreApplicantResponse, error) {
+	// Validate input
+	if req.Id <= 0 {
+		return nil, invalidField("id", "id must be positive")
+	}
+
+	s.log(ctx).Debug("restoring applicant", zap.Int64("id", req.Id))
+
+	var applicant sqlc.Applicant
+	err := s.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		var err error
+		applicant, err = q.RestoreApplicant(ctx, req.Id)
+		if err != nil {
+			return err
+		}
+
+		deleted := applicant
+		deleted.DeletedAt = sql.NullTime{Valid: true}
+		return s.recordAudit(ctx, q, applicantsv1.ApplicantsService_RestoreApplicant_FullMethodName, req.Id, &deleted, &applicant)
+	})
+	if err != nil {
+		// Not found covers applicants that are not deleted; AlreadyExists means the
+		// email has been reused by a new applicant since the delete
+		return nil, s.translateError(ctx, err, "restore applicant", applicantResource(req.Id))
+	}
+
+	return &applicantsv1.RestoreApplicantResponse{
+		Applicant: util.DbApplicantToProto(&applicant),
+	}, nil
+}
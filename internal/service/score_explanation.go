@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+	"github.com/Thrun12/golang-assignment/internal/scoring"
+	"github.com/Thrun12/golang-assignment/internal/util"
+)
+
+// GetApplicantScoreExplanation explains how an applicant's overall score was calculated
+func (s *ApplicantService) GetApplicantScoreExplanation(ctx context.Context, req *applicantsv1.GetApplicantScoreExplanationRequest) (*applicantsv1.GetApplicantScoreExplanationResponse, error) {
+	// Validate input
+	if req.Id <= 0 {
+		return nil, invalidField("id", "id must be positive")
+	}
+
+	s.log(ctx).Debug("explaining applicant score", zap.Int64("id", req.Id))
+
+	applicant, err := s.queries.GetApplicant(ctx, req.Id)
+	if err != nil {
+		return nil, s.translateError(ctx, err, "get applicant", applicantResource(req.Id))
+	}
+
+	breakdown := s.explainScore(&applicant)
+
+	return &applicantsv1.GetApplicantScoreExplanationResponse{
+		Breakdown:          util.ScoreBreakdownToProto(&breakdown),
+		StoredOverallScore: util.RoundToTwoDecimals(applicant.OverallScore),
+		Stale:              breakdown.FinalScore != util.RoundToTwoDecimals(applicant.OverallScore),
+	}, nil
+}
+
+// explainScore recalculates an applicant's score with the model recorded on
+// the row, falling back to the current model for its position if that model
+// is no longer configured
+func (s *ApplicantService) explainScore(app *sqlc.Applicant) scoring.Breakdown {
+	model, ok := s.scoringModels.Get(app.ScoringModelVersion)
+	if !ok {
+		model = s.scoringModels.ForPosition(app.Position)
+	}
+	return model.Explain(scoringInput(app))
+}
+
+// scoringInput extracts the scoring inputs of a stored applicant
+func scoringInput(app *sqlc.Applicant) scoring.Input {
+	return scoring.Input{
+		Name:               app.Name,
+		Position:           app.Position,
+		Skills:             app.Skills,
+		YearsExperience:    app.YearsExperience,
+		InterviewScore:     app.InterviewScore,
+		CulturalFitScore:   app.CulturalFitScore,
+		TechnicalScore:     app.TechnicalScore,
+		CanExitVim:         app.CanExitVim,
+		KnowsGo:            app.KnowsGo,
+		DebugsInProduction: app.DebugsInProduction,
+	}
+}
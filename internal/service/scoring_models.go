@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/util"
+)
+
+// ListScoringModels lists the configured scoring models
+func (s *ApplicantService) ListScoringModels(ctx context.Context, req *applicantsv1.ListScoringModelsRequest) (*applicantsv1.ListScoringModelsResponse, error) {
+	s.log(ctx).Debug("listing scoring models")
+
+	defaultVersion := s.scoringModels.DefaultVersion()
+	models := s.scoringModels.List()
+
+	protoModels := make([]*applicantsv1.ScoringModel, len(models))
+	for i, m := range models {
+		protoModels[i] = util.ScoringModelToProto(m, m.Version == defaultVersion)
+	}
+
+	return &applicantsv1.ListScoringModelsResponse{
+		Models: protoModels,
+	}, nil
+}
+
+// GetScoringModel retrieves a scoring model by version
+func (s *ApplicantService) GetScoringModel(ctx context.Context, req *applicantsv1.GetScoringModelRequest) (*applicantsv1.GetScoringModelResponse, error) {
+	// Validate input
+	if strings.TrimSpace(req.Version) == "" {
+		return nil, invalidField("version", "version is required")
+	}
+
+	s.log(ctx).Debug("getting scoring model", zap.String("version", req.Version))
+
+	model, ok := s.scoringModels.Get(req.Version)
+	if !ok {
+		return nil, s.translateError(ctx, sql.ErrNoRows, "get scoring model", &errdetails.ResourceInfo{
+			ResourceType: "scoring model",
+			ResourceName: req.Version,
+		})
+	}
+
+	return &applicantsv1.GetScoringModelResponse{
+		Model: util.ScoringModelToProto(model, model.Version == s.scoringModels.DefaultVersion()),
+	}, nil
+}
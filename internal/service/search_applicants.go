@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/auth"
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+	"github.com/Thrun12/golang-assignment/internal/redact"
+	"github.com/Thrun12/golang-assignment/internal/util"
+)
+
+// SearchApplicants performs full-text and fuzzy search over applicants
+func (s *ApplicantService) SearchApplicants(ctx context.Context, req *applicantsv1.SearchApplicantsRequest) (*applicantsv1.SearchApplicantsResponse, error) {
+	// Validate input
+	query := strings.TrimSpace(req.Q)
+	if query == "" {
+		return nil, invalidField("q", "q is required")
+	}
+	if len(query) > 200 {
+		return nil, invalidField("q", "q must be at most 200 characters")
+	}
+
+	// Default limit
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	s.log(ctx).Debug("searching applicants",
+		zap.String("q", query),
+		zap.Int32("limit", limit),
+	)
+
+	// Only match on email where the caller sees it, so a search cannot confirm
+	// addresses the results would redact
+	searchEmail, emailAgency := true, ""
+	if policy, ok := redact.FromContext(ctx); ok {
+		principal, _ := auth.FromContext(ctx)
+		searchEmail, emailAgency = policy.Visibility(principal, "email")
+	}
+
+	rows, err := s.queries.SearchApplicants(ctx, sqlc.SearchApplicantsParams{
+		Query:       query,
+		SearchEmail: searchEmail,
+		EmailAgency: emailAgency,
+		PageSize:    limit,
+	})
+	if err != nil {
+		return nil, s.translateError(ctx, err, "search applicants", nil)
+	}
+
+	// Convert to proto
+	results := make([]*applicantsv1.SearchResult, len(rows))
+	for i, row := range rows {
+		results[i] = &applicantsv1.SearchResult{
+			Applicant: util.DbApplicantToProto(&row.Applicant),
+			Rank:      row.Rank,
+			Snippet:   row.Snippet,
+		}
+	}
+
+	return &applicantsv1.SearchApplicantsResponse{
+		Results: results,
+	}, nil
+}
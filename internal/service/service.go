@@ -1,23 +1,41 @@
 package service
 
 import (
+	"context"
+
 	"go.uber.org/zap"
 
 	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
-	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+	"github.com/Thrun12/golang-assignment/internal/db"
+	"github.com/Thrun12/golang-assignment/internal/requestid"
+	"github.com/Thrun12/golang-assignment/internal/scoring"
 )
 
 // ApplicantService provides business logic for applicant operations and implements the gRPC service
 type ApplicantService struct {
 	applicantsv1.UnimplementedApplicantsServiceServer
-	queries sqlc.Querier
-	logger  *zap.Logger
+	queries       db.Store
+	scoringModels *scoring.Registry
+	changes       *db.ChangeFeed
+	logger        *zap.Logger
 }
 
-// NewApplicantService creates a new applicant service
-func NewApplicantService(queries sqlc.Querier, logger *zap.Logger) *ApplicantService {
+// NewApplicantService creates a new applicant service. changes feeds
+// WatchApplicants and may be nil for services that do not serve it.
+func NewApplicantService(queries db.Store, scoringModels *scoring.Registry, changes *db.ChangeFeed, logger *zap.Logger) *ApplicantService {
 	return &ApplicantService{
-		queries: queries,
-		logger:  logger,
+		queries:       queries,
+		scoringModels: scoringModels,
+		changes:       changes,
+		logger:        logger,
+	}
+}
+
+// log returns the service logger annotated with the request ID of ctx, so
+// every log line of a request can be correlated with its access log
+func (s *ApplicantService) log(ctx context.Context) *zap.Logger {
+	if id := requestid.FromContext(ctx); id != "" {
+		return s.logger.With(zap.String("request_id", id))
 	}
+	return s.logger
 }
@@ -3,14 +3,29 @@ package service
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/lib/pq"
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/auth"
 	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+	"github.com/Thrun12/golang-assignment/internal/logging"
+	"github.com/Thrun12/golang-assignment/internal/redact"
+	"github.com/Thrun12/golang-assignment/internal/requestid"
+	"github.com/Thrun12/golang-assignment/internal/scoring"
+	"github.com/Thrun12/golang-assignment/internal/util"
 )
 
 func TestGetApplicant(t *testing.T) {
@@ -148,6 +163,43 @@ func TestListApplicants(t *testing.T) {
 		}
 	})
 
+	t.Run("Email only matched where the caller sees it", func(t *testing.T) {
+		var got sqlc.SearchApplicantsParams
+		mockQ := &mockQuerier{
+			searchFunc: func(ctx context.Context, params sqlc.SearchApplicantsParams) ([]sqlc.SearchApplicantsRow, error) {
+				got = params
+				return nil, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		policyCtx := redact.NewContext(ctx, redact.DefaultPolicy())
+		tests := []struct {
+			name        string
+			ctx         context.Context
+			searchEmail bool
+			emailAgency string
+		}{
+			{"No policy", ctx, true, ""},
+			{"Interviewer", auth.NewContext(policyCtx, &auth.Principal{Subject: "i", Roles: []string{"interviewer"}}), true, ""},
+			{"Agency", auth.NewContext(policyCtx, &auth.Principal{Subject: "a", Roles: []string{"agency"}, Agency: "talent-co"}), false, "talent-co"},
+			{"Anonymous", policyCtx, false, ""},
+		}
+		for _, tt := range tests {
+			if _, err := service.SearchApplicants(tt.ctx, &applicantsv1.SearchApplicantsRequest{Q: "jane@example.com"}); err != nil {
+				t.Fatalf("%s: expected no error, got: %v", tt.name, err)
+			}
+			if got.SearchEmail != tt.searchEmail || got.EmailAgency != tt.emailAgency {
+				t.Errorf("%s: expected search_email=%v email_agency=%q, got %v and %q",
+					tt.name, tt.searchEmail, tt.emailAgency, got.SearchEmail, got.EmailAgency)
+			}
+		}
+	})
+
 	t.Run("Limit capping at 100", func(t *testing.T) {
 		mockQ := &mockQuerier{
 			listFunc: func(ctx context.Context, params sqlc.ListApplicantsParams) ([]sqlc.Applicant, error) {
@@ -247,6 +299,64 @@ func TestListApplicants(t *testing.T) {
 		}
 	})
 
+	t.Run("Filtering by skills", func(t *testing.T) {
+		checkSkills := func(anySkills, allSkills []string) {
+			if len(anySkills) != 2 || anySkills[0] != "go" || anySkills[1] != "rust" {
+				t.Errorf("Expected normalized skills_any [go rust], got %v", anySkills)
+			}
+			if len(allSkills) != 1 || allSkills[0] != "kubernetes" {
+				t.Errorf("Expected normalized skills_all [kubernetes], got %v", allSkills)
+			}
+		}
+
+		mockQ := &mockQuerier{
+			listFunc: func(ctx context.Context, params sqlc.ListApplicantsParams) ([]sqlc.Applicant, error) {
+				checkSkills(params.SkillsAny, params.SkillsAll)
+				return []sqlc.Applicant{{ID: 1}}, nil
+			},
+			countFunc: func(ctx context.Context, params sqlc.CountApplicantsParams) (int64, error) {
+				checkSkills(params.SkillsAny, params.SkillsAll)
+				return 1, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		_, err := service.ListApplicants(ctx, &applicantsv1.ListApplicantsRequest{
+			SkillsAny: []string{"Go", " RUST ", "go"},
+			SkillsAll: []string{"Kubernetes"},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	})
+
+	t.Run("No skill filters passes empty slices", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			listFunc: func(ctx context.Context, params sqlc.ListApplicantsParams) ([]sqlc.Applicant, error) {
+				if params.SkillsAny == nil || params.SkillsAll == nil {
+					t.Error("Expected non-nil skill filters")
+				}
+				return []sqlc.Applicant{}, nil
+			},
+			countFunc: func(ctx context.Context, params sqlc.CountApplicantsParams) (int64, error) {
+				return 0, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		if _, err := service.ListApplicants(ctx, &applicantsv1.ListApplicantsRequest{}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	})
+
 	t.Run("Repository error on list", func(t *testing.T) {
 		mockQ := &mockQuerier{
 			listFunc: func(ctx context.Context, params sqlc.ListApplicantsParams) ([]sqlc.Applicant, error) {
@@ -295,6 +405,158 @@ func TestListApplicants(t *testing.T) {
 			t.Error("Expected nil response on error")
 		}
 	})
+
+	t.Run("Offset mode returns next page token", func(t *testing.T) {
+		createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		mockQ := &mockQuerier{
+			listFunc: func(ctx context.Context, params sqlc.ListApplicantsParams) ([]sqlc.Applicant, error) {
+				return []sqlc.Applicant{
+					{ID: 7, OverallScore: 90.0, CreatedAt: createdAt},
+					{ID: 3, OverallScore: 80.0, CreatedAt: createdAt},
+				}, nil
+			},
+			countFunc: func(ctx context.Context, params sqlc.CountApplicantsParams) (int64, error) {
+				return 5, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.ListApplicants(ctx, &applicantsv1.ListApplicantsRequest{Limit: 2})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if resp.NextPageToken == "" {
+			t.Fatal("Expected next page token")
+		}
+
+		cursor, err := util.DecodePageToken(resp.NextPageToken, util.ListFilters{})
+		if err != nil {
+			t.Fatalf("Expected valid token, got: %v", err)
+		}
+		if cursor.ID != 3 || cursor.OverallScore != 80.0 || !cursor.CreatedAt.Equal(createdAt) {
+			t.Errorf("Expected cursor at last applicant, got %+v", cursor)
+		}
+	})
+
+	t.Run("Cursor mode uses keyset query", func(t *testing.T) {
+		createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		token := util.EncodePageToken(util.PageCursor{OverallScore: 80.0, CreatedAt: createdAt, ID: 3}, util.ListFilters{Position: "Developer"})
+
+		mockQ := &mockQuerier{
+			listByCursorFunc: func(ctx context.Context, params sqlc.ListApplicantsByCursorParams) ([]sqlc.Applicant, error) {
+				if params.CursorID != 3 || params.CursorScore != 80.0 || !params.CursorCreatedAt.Equal(createdAt) {
+					t.Errorf("Unexpected cursor params: %+v", params)
+				}
+				if params.PageSize != 3 {
+					t.Errorf("Expected page size limit+1 = 3, got %d", params.PageSize)
+				}
+				if params.Position != "Developer" {
+					t.Errorf("Expected position filter 'Developer', got '%s'", params.Position)
+				}
+				return []sqlc.Applicant{
+					{ID: 2, OverallScore: 70.0, CreatedAt: createdAt},
+					{ID: 1, OverallScore: 60.0, CreatedAt: createdAt},
+				}, nil
+			},
+			countFunc: func(ctx context.Context, params sqlc.CountApplicantsParams) (int64, error) {
+				return 4, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.ListApplicants(ctx, &applicantsv1.ListApplicantsRequest{
+			Limit:     2,
+			Offset:    50, // Ignored in cursor mode
+			Position:  "Developer",
+			PageToken: token,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if len(resp.Applicants) != 2 {
+			t.Errorf("Expected 2 applicants, got %d", len(resp.Applicants))
+		}
+		if resp.Offset != 0 {
+			t.Errorf("Expected offset 0 in cursor mode, got %d", resp.Offset)
+		}
+		if resp.NextPageToken != "" {
+			t.Errorf("Expected no next page token on last page, got %q", resp.NextPageToken)
+		}
+	})
+
+	t.Run("Cursor mode trims extra row and returns next token", func(t *testing.T) {
+		createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		token := util.EncodePageToken(util.PageCursor{OverallScore: 99.0, CreatedAt: createdAt, ID: 10}, util.ListFilters{})
+
+		mockQ := &mockQuerier{
+			listByCursorFunc: func(ctx context.Context, params sqlc.ListApplicantsByCursorParams) ([]sqlc.Applicant, error) {
+				return []sqlc.Applicant{
+					{ID: 9, OverallScore: 90.0, CreatedAt: createdAt},
+					{ID: 8, OverallScore: 85.0, CreatedAt: createdAt},
+				}, nil
+			},
+			countFunc: func(ctx context.Context, params sqlc.CountApplicantsParams) (int64, error) {
+				return 10, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.ListApplicants(ctx, &applicantsv1.ListApplicantsRequest{
+			Limit:     1,
+			PageToken: token,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if len(resp.Applicants) != 1 {
+			t.Fatalf("Expected 1 applicant, got %d", len(resp.Applicants))
+		}
+
+		cursor, err := util.DecodePageToken(resp.NextPageToken, util.ListFilters{})
+		if err != nil {
+			t.Fatalf("Expected valid token, got: %v", err)
+		}
+		if cursor.ID != 9 {
+			t.Errorf("Expected cursor at ID 9, got %d", cursor.ID)
+		}
+	})
+
+	t.Run("Invalid page token", func(t *testing.T) {
+		service := &ApplicantService{
+			queries: &mockQuerier{},
+			logger:  logger,
+		}
+
+		_, err := service.ListApplicants(ctx, &applicantsv1.ListApplicantsRequest{PageToken: "garbage"})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument, got %v", err)
+		}
+	})
+
+	t.Run("Page token for other filters", func(t *testing.T) {
+		service := &ApplicantService{
+			queries: &mockQuerier{},
+			logger:  logger,
+		}
+
+		token := util.EncodePageToken(util.PageCursor{OverallScore: 80.0, CreatedAt: time.Now(), ID: 3}, util.ListFilters{Position: "Developer"})
+		_, err := service.ListApplicants(ctx, &applicantsv1.ListApplicantsRequest{Position: "Designer", PageToken: token})
+		if status.Code(err) != codes.InvalidArgument || !contains(err.Error(), "different filters") {
+			t.Errorf("Expected InvalidArgument for different filters, got %v", err)
+		}
+	})
 }
 
 func TestUpdateApplicant(t *testing.T) {
@@ -303,6 +565,16 @@ func TestUpdateApplicant(t *testing.T) {
 
 	t.Run("Successful update", func(t *testing.T) {
 		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{ID: id, Status: int32(applicantsv1.ApplicantStatus_APPLICANT_STATUS_REVIEWING)}, nil
+			},
+			historyFunc: func(ctx context.Context, params sqlc.CreateStatusHistoryEntryParams) (sqlc.ApplicantStatusHistory, error) {
+				if params.FromStatus != int32(applicantsv1.ApplicantStatus_APPLICANT_STATUS_REVIEWING) ||
+					params.ToStatus != int32(applicantsv1.ApplicantStatus_APPLICANT_STATUS_INTERVIEWED) {
+					t.Errorf("Expected REVIEWING -> INTERVIEWED history entry, got %d -> %d", params.FromStatus, params.ToStatus)
+				}
+				return sqlc.ApplicantStatusHistory{ApplicantID: params.ApplicantID}, nil
+			},
 			updateFunc: func(ctx context.Context, params sqlc.UpdateApplicantParams) (sqlc.Applicant, error) {
 				if params.ID != 1 {
 					t.Errorf("Expected ID 1, got %d", params.ID)
@@ -356,6 +628,7 @@ func TestUpdateApplicant(t *testing.T) {
 			CulturalFitScore:   92.0,
 			TechnicalScore:     91.0,
 			Status:             applicantsv1.ApplicantStatus_APPLICANT_STATUS_INTERVIEWED,
+			Etag:               "*",
 		}
 
 		resp, err := service.UpdateApplicant(ctx, req)
@@ -371,207 +644,321 @@ func TestUpdateApplicant(t *testing.T) {
 		}
 	})
 
-	t.Run("Validation failure - invalid ID", func(t *testing.T) {
+	t.Run("Validation failure - empty position", func(t *testing.T) {
 		service := &ApplicantService{
 			queries: &mockQuerier{},
 			logger:  logger,
 		}
 
 		req := &applicantsv1.UpdateApplicantRequest{
-			Id:    0,
-			Name:  "Jane Doe",
-			Email: "jane@example.com",
+			Id:               1,
+			Name:             "Jane Doe",
+			Email:            "jane@example.com",
+			Position:         "",
+			InterviewScore:   85.0,
+			CulturalFitScore: 90.0,
+			TechnicalScore:   88.0,
+			Etag:             "*",
 		}
 
 		resp, err := service.UpdateApplicant(ctx, req)
-		if err == nil {
-			t.Fatal("Expected validation error, got nil")
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("Expected InvalidArgument, got %v", err)
 		}
 		if resp != nil {
 			t.Error("Expected nil response on validation error")
 		}
 	})
 
-	t.Run("Validation failure - invalid email", func(t *testing.T) {
+	t.Run("Repository error", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			updateFunc: func(ctx context.Context, params sqlc.UpdateApplicantParams) (sqlc.Applicant, error) {
+				return sqlc.Applicant{}, errors.New("database error")
+			},
+		}
+
 		service := &ApplicantService{
-			queries: &mockQuerier{},
+			queries: mockQ,
 			logger:  logger,
 		}
 
 		req := &applicantsv1.UpdateApplicantRequest{
 			Id:               1,
 			Name:             "Jane Doe",
-			Email:            "not-an-email",
+			Email:            "jane@example.com",
 			Position:         "Developer",
+			YearsExperience:  5,
 			InterviewScore:   85.0,
 			CulturalFitScore: 90.0,
 			TechnicalScore:   88.0,
+			Etag:             "*",
 		}
 
 		resp, err := service.UpdateApplicant(ctx, req)
 		if err == nil {
-			t.Fatal("Expected validation error, got nil")
+			t.Fatal("Expected error from repository, got nil")
 		}
 		if resp != nil {
-			t.Error("Expected nil response on validation error")
+			t.Error("Expected nil response on repository error")
 		}
 	})
 
-	t.Run("Validation failure - empty position", func(t *testing.T) {
-		service := &ApplicantService{
-			queries: &mockQuerier{},
-			logger:  logger,
+	storedApplicant := sqlc.Applicant{
+		ID:               1,
+		Name:             "Jane Doe",
+		Email:            "jane@example.com",
+		Position:         "Developer",
+		YearsExperience:  5,
+		Skills:           []string{"Go", "Kubernetes"},
+		InterviewScore:   85.0,
+		CulturalFitScore: 90.0,
+		TechnicalScore:   88.0,
+		OverallScore:     42.0,
+		Status:           1,
+		FunFact:          sql.NullString{String: "Knows every Vim motion", Valid: true},
+	}
+
+	t.Run("Partial update keeps unmasked fields and score", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return storedApplicant, nil
+			},
+			historyFunc: func(ctx context.Context, params sqlc.CreateStatusHistoryEntryParams) (sqlc.ApplicantStatusHistory, error) {
+				if params.ChangedBy != "recruiter@example.com" {
+					t.Errorf("Expected the change to be attributed to the caller, got %q", params.ChangedBy)
+				}
+				return sqlc.ApplicantStatusHistory{ApplicantID: params.ApplicantID}, nil
+			},
+			updateFunc: func(ctx context.Context, params sqlc.UpdateApplicantParams) (sqlc.Applicant, error) {
+				if params.Status != int32(applicantsv1.ApplicantStatus_APPLICANT_STATUS_REVIEWING) {
+					t.Errorf("Expected status REVIEWING, got %d", params.Status)
+				}
+				if params.Name != storedApplicant.Name {
+					t.Errorf("Expected name to be kept, got '%s'", params.Name)
+				}
+				if params.FunFact.String != storedApplicant.FunFact.String {
+					t.Errorf("Expected fun fact to be kept, got '%s'", params.FunFact.String)
+				}
+				if len(params.Skills) != 2 {
+					t.Errorf("Expected skills to be kept, got %v", params.Skills)
+				}
+				if params.OverallScore != storedApplicant.OverallScore {
+					t.Errorf("Expected score %.2f to be kept, got %.2f", storedApplicant.OverallScore, params.OverallScore)
+				}
+				return sqlc.Applicant{ID: params.ID, Name: params.Name, Status: params.Status}, nil
+			},
 		}
 
-		req := &applicantsv1.UpdateApplicantRequest{
-			Id:               1,
-			Name:             "Jane Doe",
-			Email:            "jane@example.com",
-			Position:         "",
-			InterviewScore:   85.0,
-			CulturalFitScore: 90.0,
-			TechnicalScore:   88.0,
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
 		}
 
-		resp, err := service.UpdateApplicant(ctx, req)
-		if err == nil {
-			t.Fatal("Expected validation error, got nil")
+		callerCtx := auth.NewContext(ctx, &auth.Principal{Subject: "recruiter@example.com", Roles: []string{"recruiter"}})
+		resp, err := service.UpdateApplicant(callerCtx, &applicantsv1.UpdateApplicantRequest{
+			Id:         1,
+			Status:     applicantsv1.ApplicantStatus_APPLICANT_STATUS_REVIEWING,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"status"}},
+			Etag:       "*",
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
 		}
-		if resp != nil {
-			t.Error("Expected nil response on validation error")
+		if resp.Applicant.Status != applicantsv1.ApplicantStatus_APPLICANT_STATUS_REVIEWING {
+			t.Errorf("Expected status REVIEWING, got %v", resp.Applicant.Status)
 		}
 	})
 
-	t.Run("Validation failure - position too short", func(t *testing.T) {
+	t.Run("Partial update recalculates score when scoring input changes", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return storedApplicant, nil
+			},
+			updateFunc: func(ctx context.Context, params sqlc.UpdateApplicantParams) (sqlc.Applicant, error) {
+				expected := util.CalculateOverallScore(
+					storedApplicant.Name,
+					storedApplicant.Skills,
+					storedApplicant.YearsExperience,
+					storedApplicant.InterviewScore,
+					storedApplicant.CulturalFitScore,
+					95.0,
+					storedApplicant.CanExitVim,
+					storedApplicant.KnowsGo,
+					storedApplicant.DebugsInProduction,
+				)
+				if params.OverallScore != expected {
+					t.Errorf("Expected recalculated score %.2f, got %.2f", expected, params.OverallScore)
+				}
+				return sqlc.Applicant{ID: params.ID, OverallScore: params.OverallScore}, nil
+			},
+		}
+
 		service := &ApplicantService{
-			queries: &mockQuerier{},
+			queries: mockQ,
 			logger:  logger,
 		}
 
-		req := &applicantsv1.UpdateApplicantRequest{
-			Id:               1,
-			Name:             "Jane Doe",
-			Email:            "jane@example.com",
-			Position:         "D",
-			InterviewScore:   85.0,
-			CulturalFitScore: 90.0,
-			TechnicalScore:   88.0,
+		_, err := service.UpdateApplicant(ctx, &applicantsv1.UpdateApplicantRequest{
+			Id:             1,
+			TechnicalScore: 95.0,
+			UpdateMask:     &fieldmaskpb.FieldMask{Paths: []string{"technical_score"}},
+			Etag:           "*",
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
 		}
+	})
 
-		resp, err := service.UpdateApplicant(ctx, req)
-		if err == nil {
-			t.Fatal("Expected validation error, got nil")
+	t.Run("Partial update clears masked field left empty", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return storedApplicant, nil
+			},
+			updateFunc: func(ctx context.Context, params sqlc.UpdateApplicantParams) (sqlc.Applicant, error) {
+				if params.FunFact.Valid {
+					t.Errorf("Expected fun fact to be cleared, got '%s'", params.FunFact.String)
+				}
+				return sqlc.Applicant{ID: params.ID}, nil
+			},
 		}
-		if resp != nil {
-			t.Error("Expected nil response on validation error")
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		_, err := service.UpdateApplicant(ctx, &applicantsv1.UpdateApplicantRequest{
+			Id:         1,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"fun_fact"}},
+			Etag:       "*",
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
 		}
 	})
 
-	t.Run("Validation failure - negative github stars", func(t *testing.T) {
+	t.Run("Partial update with invalid mask path", func(t *testing.T) {
 		service := &ApplicantService{
 			queries: &mockQuerier{},
 			logger:  logger,
 		}
 
-		req := &applicantsv1.UpdateApplicantRequest{
-			Id:               1,
-			Name:             "Jane Doe",
-			Email:            "jane@example.com",
-			Position:         "Developer",
-			GithubStars:      -10,
-			InterviewScore:   85.0,
-			CulturalFitScore: 90.0,
-			TechnicalScore:   88.0,
-		}
-
-		resp, err := service.UpdateApplicant(ctx, req)
-		if err == nil {
-			t.Fatal("Expected validation error, got nil")
-		}
-		if resp != nil {
-			t.Error("Expected nil response on validation error")
+		for _, path := range []string{"not_a_field", "id", "update_mask"} {
+			_, err := service.UpdateApplicant(ctx, &applicantsv1.UpdateApplicantRequest{
+				Id:         1,
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{path}},
+				Etag:       "*",
+			})
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Expected InvalidArgument for path %q, got %v", path, err)
+			}
 		}
 	})
 
-	t.Run("Validation failure - interview score out of range", func(t *testing.T) {
+	t.Run("Partial update with nested mask path", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return storedApplicant, nil
+			},
+		}
 		service := &ApplicantService{
-			queries: &mockQuerier{},
+			queries: mockQ,
 			logger:  logger,
 		}
 
-		req := &applicantsv1.UpdateApplicantRequest{
-			Id:               1,
-			Name:             "Jane Doe",
-			Email:            "jane@example.com",
-			Position:         "Developer",
-			InterviewScore:   150.0,
-			CulturalFitScore: 90.0,
-			TechnicalScore:   88.0,
+		for _, path := range []string{"update_mask.paths", "etag.x", "name.first"} {
+			_, err := service.UpdateApplicant(ctx, &applicantsv1.UpdateApplicantRequest{
+				Id:         1,
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{path}},
+				Etag:       "*",
+			})
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Expected InvalidArgument for path %q, got %v", path, err)
+			}
 		}
+	})
 
-		resp, err := service.UpdateApplicant(ctx, req)
-		if err == nil {
-			t.Fatal("Expected validation error, got nil")
-		}
-		if resp != nil {
-			t.Error("Expected nil response on validation error")
+	t.Run("Partial update of missing applicant", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{}, sql.ErrNoRows
+			},
 		}
-	})
 
-	t.Run("Validation failure - cultural fit score out of range", func(t *testing.T) {
 		service := &ApplicantService{
-			queries: &mockQuerier{},
+			queries: mockQ,
 			logger:  logger,
 		}
 
-		req := &applicantsv1.UpdateApplicantRequest{
-			Id:               1,
-			Name:             "Jane Doe",
-			Email:            "jane@example.com",
-			Position:         "Developer",
-			InterviewScore:   85.0,
-			CulturalFitScore: -10.0,
-			TechnicalScore:   88.0,
+		_, err := service.UpdateApplicant(ctx, &applicantsv1.UpdateApplicantRequest{
+			Id:         999,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"status"}},
+			Etag:       "*",
+		})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound, got %v", err)
 		}
+	})
 
-		resp, err := service.UpdateApplicant(ctx, req)
-		if err == nil {
-			t.Fatal("Expected validation error, got nil")
-		}
-		if resp != nil {
-			t.Error("Expected nil response on validation error")
+	t.Run("Partial update validates merged result", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return storedApplicant, nil
+			},
 		}
-	})
 
-	t.Run("Validation failure - technical score out of range", func(t *testing.T) {
 		service := &ApplicantService{
-			queries: &mockQuerier{},
+			queries: mockQ,
 			logger:  logger,
 		}
 
-		req := &applicantsv1.UpdateApplicantRequest{
-			Id:               1,
-			Name:             "Jane Doe",
-			Email:            "jane@example.com",
-			Position:         "Developer",
-			InterviewScore:   85.0,
-			CulturalFitScore: 90.0,
-			TechnicalScore:   110.0,
+		_, err := service.UpdateApplicant(ctx, &applicantsv1.UpdateApplicantRequest{
+			Id:         1,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"email"}},
+			Etag:       "*",
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument, got %v", err)
 		}
+	})
 
-		resp, err := service.UpdateApplicant(ctx, req)
-		if err == nil {
-			t.Fatal("Expected validation error, got nil")
+	t.Run("Illegal status jump", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return storedApplicant, nil
+			},
+			updateFunc: func(ctx context.Context, params sqlc.UpdateApplicantParams) (sqlc.Applicant, error) {
+				t.Error("Expected no update for an illegal status jump")
+				return sqlc.Applicant{}, nil
+			},
 		}
-		if resp != nil {
-			t.Error("Expected nil response on validation error")
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		_, err := service.UpdateApplicant(ctx, &applicantsv1.UpdateApplicantRequest{
+			Id:         1,
+			Status:     applicantsv1.ApplicantStatus_APPLICANT_STATUS_HIRED,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"status"}},
+			Etag:       "*",
+		})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("Expected FailedPrecondition, got %v", err)
 		}
 	})
 
-	t.Run("Repository error", func(t *testing.T) {
+	t.Run("Unchanged status records no history", func(t *testing.T) {
 		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return storedApplicant, nil
+			},
 			updateFunc: func(ctx context.Context, params sqlc.UpdateApplicantParams) (sqlc.Applicant, error) {
-				return sqlc.Applicant{}, errors.New("database error")
+				if params.Status != storedApplicant.Status {
+					t.Errorf("Expected status %d to be kept, got %d", storedApplicant.Status, params.Status)
+				}
+				return sqlc.Applicant{ID: params.ID, Status: params.Status}, nil
 			},
 		}
 
@@ -580,7 +967,9 @@ func TestUpdateApplicant(t *testing.T) {
 			logger:  logger,
 		}
 
-		req := &applicantsv1.UpdateApplicantRequest{
+		// Unspecified status keeps the current one; historyFunc is unset so a
+		// history write would fail the update
+		_, err := service.UpdateApplicant(ctx, &applicantsv1.UpdateApplicantRequest{
 			Id:               1,
 			Name:             "Jane Doe",
 			Email:            "jane@example.com",
@@ -589,14 +978,53 @@ func TestUpdateApplicant(t *testing.T) {
 			InterviewScore:   85.0,
 			CulturalFitScore: 90.0,
 			TechnicalScore:   88.0,
+			Etag:             "*",
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
 		}
+	})
 
-		resp, err := service.UpdateApplicant(ctx, req)
-		if err == nil {
-			t.Fatal("Expected error from repository, got nil")
+	t.Run("Stale etag is rejected", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{ID: id, Name: "Jane Doe", Email: "jane@example.com", Position: "Developer", Version: 3}, nil
+			},
+			updateFunc: func(ctx context.Context, params sqlc.UpdateApplicantParams) (sqlc.Applicant, error) {
+				t.Error("Expected no update with a stale etag")
+				return sqlc.Applicant{}, nil
+			},
 		}
-		if resp != nil {
-			t.Error("Expected nil response on repository error")
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		_, err := service.UpdateApplicant(ctx, &applicantsv1.UpdateApplicantRequest{
+			Id:         1,
+			FunFact:    "Changed concurrently",
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"fun_fact"}},
+			Etag:       "2",
+		})
+		if status.Code(err) != codes.Aborted {
+			t.Errorf("Expected Aborted, got %v", err)
+		}
+	})
+
+	t.Run("Missing etag", func(t *testing.T) {
+		service := &ApplicantService{
+			queries: &mockQuerier{},
+			logger:  logger,
+		}
+
+		_, err := service.UpdateApplicant(ctx, &applicantsv1.UpdateApplicantRequest{
+			Id:         1,
+			FunFact:    "No etag",
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"fun_fact"}},
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument, got %v", err)
 		}
 	})
 }
@@ -607,11 +1035,14 @@ func TestDeleteApplicant(t *testing.T) {
 
 	t.Run("Successful deletion", func(t *testing.T) {
 		mockQ := &mockQuerier{
-			deleteFunc: func(ctx context.Context, id int64) error {
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{ID: id, Version: 4}, nil
+			},
+			deleteFunc: func(ctx context.Context, id int64) (int64, error) {
 				if id != 1 {
 					t.Errorf("Expected ID 1, got %d", id)
 				}
-				return nil
+				return 1, nil
 			},
 		}
 
@@ -620,7 +1051,7 @@ func TestDeleteApplicant(t *testing.T) {
 			logger:  logger,
 		}
 
-		resp, err := service.DeleteApplicant(ctx, &applicantsv1.DeleteApplicantRequest{Id: 1})
+		resp, err := service.DeleteApplicant(ctx, &applicantsv1.DeleteApplicantRequest{Id: 1, Etag: "4"})
 		if err != nil {
 			t.Fatalf("Expected no error, got: %v", err)
 		}
@@ -647,8 +1078,11 @@ func TestDeleteApplicant(t *testing.T) {
 
 	t.Run("Repository error", func(t *testing.T) {
 		mockQ := &mockQuerier{
-			deleteFunc: func(ctx context.Context, id int64) error {
-				return errors.New("database error")
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{ID: id}, nil
+			},
+			deleteFunc: func(ctx context.Context, id int64) (int64, error) {
+				return 0, errors.New("database error")
 			},
 		}
 
@@ -657,7 +1091,7 @@ func TestDeleteApplicant(t *testing.T) {
 			logger:  logger,
 		}
 
-		resp, err := service.DeleteApplicant(ctx, &applicantsv1.DeleteApplicantRequest{Id: 1})
+		resp, err := service.DeleteApplicant(ctx, &applicantsv1.DeleteApplicantRequest{Id: 1, Etag: "*"})
 		if err == nil {
 			t.Fatal("Expected error from repository, got nil")
 		}
@@ -665,25 +1099,102 @@ func TestDeleteApplicant(t *testing.T) {
 			t.Error("Expected nil response on repository error")
 		}
 	})
+
+	t.Run("Applicant not found", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{}, sql.ErrNoRows
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		_, err := service.DeleteApplicant(ctx, &applicantsv1.DeleteApplicantRequest{Id: 999, Etag: "*"})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound, got %v", err)
+		}
+	})
+
+	t.Run("Missing etag", func(t *testing.T) {
+		service := &ApplicantService{
+			queries: &mockQuerier{},
+			logger:  logger,
+		}
+
+		_, err := service.DeleteApplicant(ctx, &applicantsv1.DeleteApplicantRequest{Id: 1})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument, got %v", err)
+		}
+	})
+
+	t.Run("Stale etag", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{ID: id, Version: 5}, nil
+			},
+			deleteFunc: func(ctx context.Context, id int64) (int64, error) {
+				t.Error("Expected no delete with a stale etag")
+				return 1, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		_, err := service.DeleteApplicant(ctx, &applicantsv1.DeleteApplicantRequest{Id: 1, Etag: "4"})
+		st, _ := status.FromError(err)
+		if st.Code() != codes.Aborted {
+			t.Fatalf("Expected Aborted, got %v", err)
+		}
+
+		found := false
+		for _, detail := range st.Details() {
+			if failure, ok := detail.(*errdetails.PreconditionFailure); ok {
+				for _, v := range failure.GetViolations() {
+					found = found || v.GetType() == util.ETagPreconditionType
+				}
+			}
+		}
+		if !found {
+			t.Error("Expected an ETAG precondition failure detail")
+		}
+	})
+
+	t.Run("Etag from If-Match metadata", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{ID: id, Version: 5}, nil
+			},
+			deleteFunc: func(ctx context.Context, id int64) (int64, error) {
+				return 1, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		mdCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("if-match", `"5"`))
+		if _, err := service.DeleteApplicant(mdCtx, &applicantsv1.DeleteApplicantRequest{Id: 1}); err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+	})
 }
 
-func TestGetBestApplicant(t *testing.T) {
+func TestRestoreApplicant(t *testing.T) {
 	logger := zap.NewNop()
 	ctx := context.Background()
 
-	t.Run("Successful retrieval", func(t *testing.T) {
+	t.Run("Successful restore", func(t *testing.T) {
 		mockQ := &mockQuerier{
-			getBestFunc: func(ctx context.Context) (sqlc.Applicant, error) {
-				return sqlc.Applicant{
-					ID:           1,
-					Name:         "Jonathan Søholm-Boesen",
-					Email:        "jonathan@example.com",
-					Position:     "Senior Go Developer",
-					OverallScore: 99.5,
-					Status:       1,
-					CreatedAt:    time.Now(),
-					UpdatedAt:    time.Now(),
-				}, nil
+			restoreFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{ID: id, Name: "Jane Doe"}, nil
 			},
 		}
 
@@ -692,24 +1203,37 @@ func TestGetBestApplicant(t *testing.T) {
 			logger:  logger,
 		}
 
-		resp, err := service.GetBestApplicant(ctx, &applicantsv1.GetBestApplicantRequest{})
+		resp, err := service.RestoreApplicant(ctx, &applicantsv1.RestoreApplicantRequest{Id: 3})
 		if err != nil {
 			t.Fatalf("Expected no error, got: %v", err)
 		}
+		if resp.Applicant.Id != 3 {
+			t.Errorf("Expected applicant 3, got %d", resp.Applicant.Id)
+		}
+	})
 
-		if resp.Applicant == nil {
-			t.Fatal("Expected applicant, got nil")
+	t.Run("Applicant not deleted", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			restoreFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{}, sql.ErrNoRows
+			},
 		}
 
-		if resp.Reason == "" {
-			t.Error("Expected reason to be populated")
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		_, err := service.RestoreApplicant(ctx, &applicantsv1.RestoreApplicantRequest{Id: 3})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound, got %v", err)
 		}
 	})
 
-	t.Run("Repository error", func(t *testing.T) {
+	t.Run("Email reused since delete", func(t *testing.T) {
 		mockQ := &mockQuerier{
-			getBestFunc: func(ctx context.Context) (sqlc.Applicant, error) {
-				return sqlc.Applicant{}, errors.New("database error")
+			restoreFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{}, &pq.Error{Code: "23505", Constraint: "idx_applicants_email_active"}
 			},
 		}
 
@@ -718,23 +1242,21 @@ func TestGetBestApplicant(t *testing.T) {
 			logger:  logger,
 		}
 
-		resp, err := service.GetBestApplicant(ctx, &applicantsv1.GetBestApplicantRequest{})
-		if err == nil {
-			t.Fatal("Expected error from repository, got nil")
-		}
-		if resp != nil {
-			t.Error("Expected nil response on repository error")
+		_, err := service.RestoreApplicant(ctx, &applicantsv1.RestoreApplicantRequest{Id: 3})
+		if status.Code(err) != codes.AlreadyExists {
+			t.Errorf("Expected AlreadyExists, got %v", err)
 		}
 	})
+}
 
-	t.Run("GenerateBestApplicantReason - Jonathan special case", func(t *testing.T) {
+func TestPurgeApplicant(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	t.Run("Successful purge", func(t *testing.T) {
 		mockQ := &mockQuerier{
-			getBestFunc: func(ctx context.Context) (sqlc.Applicant, error) {
-				return sqlc.Applicant{
-					ID:           1,
-					Name:         "Jonathan Søholm-Boesen",
-					OverallScore: 99.5,
-				}, nil
+			purgeFunc: func(ctx context.Context, id int64) (int64, error) {
+				return 1, nil
 			},
 		}
 
@@ -743,25 +1265,22 @@ func TestGetBestApplicant(t *testing.T) {
 			logger:  logger,
 		}
 
-		resp, err := service.GetBestApplicant(ctx, &applicantsv1.GetBestApplicantRequest{})
+		resp, err := service.PurgeApplicant(ctx, &applicantsv1.PurgeApplicantRequest{Id: 3})
 		if err != nil {
 			t.Fatalf("Expected no error, got: %v", err)
 		}
-
-		// Should contain special keywords
-		if !containsAny(resp.Reason, []string{"Danish", "time travel"}) {
-			t.Errorf("Expected special reason for Jonathan, got: %s", resp.Reason)
+		if !resp.Success {
+			t.Error("Expected success to be true")
 		}
 	})
 
-	t.Run("GenerateBestApplicantReason - generic case", func(t *testing.T) {
+	t.Run("Active applicant cannot be purged", func(t *testing.T) {
 		mockQ := &mockQuerier{
-			getBestFunc: func(ctx context.Context) (sqlc.Applicant, error) {
-				return sqlc.Applicant{
-					ID:           2,
-					Name:         "Jane Doe",
-					OverallScore: 95.5,
-				}, nil
+			purgeFunc: func(ctx context.Context, id int64) (int64, error) {
+				return 0, nil
+			},
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{ID: id}, nil
 			},
 		}
 
@@ -770,18 +1289,1141 @@ func TestGetBestApplicant(t *testing.T) {
 			logger:  logger,
 		}
 
-		resp, err := service.GetBestApplicant(ctx, &applicantsv1.GetBestApplicantRequest{})
-		if err != nil {
-			t.Fatalf("Expected no error, got: %v", err)
+		_, err := service.PurgeApplicant(ctx, &applicantsv1.PurgeApplicantRequest{Id: 3})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("Expected FailedPrecondition, got %v", err)
 		}
+	})
 
-		// Should contain the score
+	t.Run("Applicant not found", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			purgeFunc: func(ctx context.Context, id int64) (int64, error) {
+				return 0, nil
+			},
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{}, sql.ErrNoRows
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		_, err := service.PurgeApplicant(ctx, &applicantsv1.PurgeApplicantRequest{Id: 999})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound, got %v", err)
+		}
+	})
+}
+
+func TestGetBestApplicant(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	t.Run("Successful retrieval", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getBestFunc: func(ctx context.Context) (sqlc.Applicant, error) {
+				return sqlc.Applicant{
+					ID:           1,
+					Name:         "Jonathan Søholm-Boesen",
+					Email:        "jonathan@example.com",
+					Position:     "Senior Go Developer",
+					OverallScore: 99.5,
+					Status:       1,
+					CreatedAt:    time.Now(),
+					UpdatedAt:    time.Now(),
+				}, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.GetBestApplicant(ctx, &applicantsv1.GetBestApplicantRequest{})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if resp.Applicant == nil {
+			t.Fatal("Expected applicant, got nil")
+		}
+
+		if resp.Reason == "" {
+			t.Error("Expected reason to be populated")
+		}
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getBestFunc: func(ctx context.Context) (sqlc.Applicant, error) {
+				return sqlc.Applicant{}, errors.New("database error")
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.GetBestApplicant(ctx, &applicantsv1.GetBestApplicantRequest{})
+		if err == nil {
+			t.Fatal("Expected error from repository, got nil")
+		}
+		if resp != nil {
+			t.Error("Expected nil response on repository error")
+		}
+	})
+
+	t.Run("GenerateBestApplicantReason - Jonathan special case", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getBestFunc: func(ctx context.Context) (sqlc.Applicant, error) {
+				return sqlc.Applicant{
+					ID:           1,
+					Name:         "Jonathan Søholm-Boesen",
+					OverallScore: 99.5,
+				}, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.GetBestApplicant(ctx, &applicantsv1.GetBestApplicantRequest{})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		// Should contain special keywords
+		if !containsAny(resp.Reason, []string{"Danish", "time travel"}) {
+			t.Errorf("Expected special reason for Jonathan, got: %s", resp.Reason)
+		}
+	})
+
+	t.Run("GenerateBestApplicantReason - generic case", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getBestFunc: func(ctx context.Context) (sqlc.Applicant, error) {
+				return sqlc.Applicant{
+					ID:           2,
+					Name:         "Jane Doe",
+					OverallScore: 95.5,
+				}, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.GetBestApplicant(ctx, &applicantsv1.GetBestApplicantRequest{})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		// Should contain the score
 		if !containsAny(resp.Reason, []string{"95.50"}) {
 			t.Errorf("Expected reason to contain score, got: %s", resp.Reason)
 		}
 	})
 }
 
+func TestSearchApplicants(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	t.Run("Successful search", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			searchFunc: func(ctx context.Context, params sqlc.SearchApplicantsParams) ([]sqlc.SearchApplicantsRow, error) {
+				if params.Query != "rust" {
+					t.Errorf("Expected trimmed query 'rust', got '%s'", params.Query)
+				}
+				if params.PageSize != 10 {
+					t.Errorf("Expected default page size 10, got %d", params.PageSize)
+				}
+				return []sqlc.SearchApplicantsRow{
+					{
+						Applicant: sqlc.Applicant{ID: 4, Name: "Charlie Davis"},
+						Rank:      0.75,
+						Snippet:   "Go, <mark>Rust</mark>, React",
+					},
+				}, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.SearchApplicants(ctx, &applicantsv1.SearchApplicantsRequest{Q: "  rust  "})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if len(resp.Results) != 1 {
+			t.Fatalf("Expected 1 result, got %d", len(resp.Results))
+		}
+		if resp.Results[0].Applicant.Id != 4 {
+			t.Errorf("Expected applicant ID 4, got %d", resp.Results[0].Applicant.Id)
+		}
+		if resp.Results[0].Snippet != "Go, <mark>Rust</mark>, React" {
+			t.Errorf("Unexpected snippet: %s", resp.Results[0].Snippet)
+		}
+	})
+
+	t.Run("Limit capping at 100", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			searchFunc: func(ctx context.Context, params sqlc.SearchApplicantsParams) ([]sqlc.SearchApplicantsRow, error) {
+				if params.PageSize != 100 {
+					t.Errorf("Expected capped page size 100, got %d", params.PageSize)
+				}
+				return nil, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		if _, err := service.SearchApplicants(ctx, &applicantsv1.SearchApplicantsRequest{Q: "go", Limit: 500}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	})
+
+	t.Run("Validation failure - empty query", func(t *testing.T) {
+		service := &ApplicantService{
+			queries: &mockQuerier{},
+			logger:  logger,
+		}
+
+		_, err := service.SearchApplicants(ctx, &applicantsv1.SearchApplicantsRequest{Q: "   "})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument, got %v", err)
+		}
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			searchFunc: func(ctx context.Context, params sqlc.SearchApplicantsParams) ([]sqlc.SearchApplicantsRow, error) {
+				return nil, errors.New("database error")
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.SearchApplicants(ctx, &applicantsv1.SearchApplicantsRequest{Q: "go"})
+		if err == nil {
+			t.Fatal("Expected error from repository, got nil")
+		}
+		if resp != nil {
+			t.Error("Expected nil response on repository error")
+		}
+	})
+}
+
+func TestScoringModels(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	registry, err := scoring.NewRegistry([]scoring.Model{
+		{
+			Version:   "platform-v1",
+			Positions: []string{"Platform Engineer"},
+			Weights:   scoring.Weights{Technical: 1.0},
+		},
+	}, "")
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+
+	t.Run("List scoring models", func(t *testing.T) {
+		service := &ApplicantService{
+			queries:       &mockQuerier{},
+			scoringModels: registry,
+			logger:        logger,
+		}
+
+		resp, err := service.ListScoringModels(ctx, &applicantsv1.ListScoringModelsRequest{})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if len(resp.Models) != 2 {
+			t.Fatalf("Expected 2 models, got %d", len(resp.Models))
+		}
+		if !resp.Models[0].IsDefault || resp.Models[0].Version != scoring.BuiltinVersion {
+			t.Errorf("Expected built-in model first and default, got %s", resp.Models[0].Version)
+		}
+		if len(resp.Models[0].Rules) == 0 {
+			t.Error("Expected built-in model rules to be returned")
+		}
+	})
+
+	t.Run("Get scoring model", func(t *testing.T) {
+		service := &ApplicantService{
+			queries:       &mockQuerier{},
+			scoringModels: registry,
+			logger:        logger,
+		}
+
+		resp, err := service.GetScoringModel(ctx, &applicantsv1.GetScoringModelRequest{Version: "platform-v1"})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if resp.Model.Weights.Technical != 1.0 {
+			t.Errorf("Expected technical weight 1.0, got %.2f", resp.Model.Weights.Technical)
+		}
+
+		_, err = service.GetScoringModel(ctx, &applicantsv1.GetScoringModelRequest{Version: "missing"})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound, got %v", err)
+		}
+	})
+
+	t.Run("Create uses model for position and records version", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			createFunc: func(ctx context.Context, params sqlc.CreateApplicantParams) (sqlc.Applicant, error) {
+				if params.ScoringModelVersion != "platform-v1" {
+					t.Errorf("Expected version platform-v1, got %s", params.ScoringModelVersion)
+				}
+				if params.OverallScore != 80.0 {
+					t.Errorf("Expected score 80.00 from technical weight only, got %.2f", params.OverallScore)
+				}
+				return sqlc.Applicant{ID: 1, ScoringModelVersion: params.ScoringModelVersion}, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries:       mockQ,
+			scoringModels: registry,
+			logger:        logger,
+		}
+
+		resp, err := service.CreateApplicant(ctx, &applicantsv1.CreateApplicantRequest{
+			Name:             "Jane Doe",
+			Email:            "jane@example.com",
+			Position:         "Platform Engineer",
+			InterviewScore:   50.0,
+			CulturalFitScore: 50.0,
+			TechnicalScore:   80.0,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if resp.Applicant.ScoringModelVersion != "platform-v1" {
+			t.Errorf("Expected version platform-v1 in response, got %s", resp.Applicant.ScoringModelVersion)
+		}
+	})
+}
+
+func TestGetApplicantScoreExplanation(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	stored := sqlc.Applicant{
+		ID:                  1,
+		Name:                "Bob Smith",
+		Position:            "Developer",
+		YearsExperience:     10,
+		Skills:              []string{"Java"},
+		InterviewScore:      65.0,
+		CulturalFitScore:    70.0,
+		TechnicalScore:      60.0,
+		DebugsInProduction:  true,
+		ScoringModelVersion: scoring.BuiltinVersion,
+	}
+	stored.OverallScore = scoring.DefaultModel().Score(scoringInput(&stored))
+
+	t.Run("Successful explanation", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return stored, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.GetApplicantScoreExplanation(ctx, &applicantsv1.GetApplicantScoreExplanationRequest{Id: 1})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if resp.Breakdown.ScoringModelVersion != scoring.BuiltinVersion {
+			t.Errorf("Expected version %s, got %s", scoring.BuiltinVersion, resp.Breakdown.ScoringModelVersion)
+		}
+		if resp.Breakdown.FinalScore != stored.OverallScore {
+			t.Errorf("Expected final score %.2f, got %.2f", stored.OverallScore, resp.Breakdown.FinalScore)
+		}
+		if resp.Stale {
+			t.Error("Expected stored score not to be stale")
+		}
+		if len(resp.Breakdown.Adjustments) == 0 || resp.Breakdown.Adjustments[0].Rule != "java_without_go" {
+			t.Errorf("Expected Java penalty first, got %+v", resp.Breakdown.Adjustments)
+		}
+	})
+
+	t.Run("Stale stored score", func(t *testing.T) {
+		staleApplicant := stored
+		staleApplicant.OverallScore = 12.34
+
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return staleApplicant, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.GetApplicantScoreExplanation(ctx, &applicantsv1.GetApplicantScoreExplanationRequest{Id: 1})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if !resp.Stale {
+			t.Error("Expected stored score to be stale")
+		}
+		if resp.StoredOverallScore != 12.34 {
+			t.Errorf("Expected stored score 12.34, got %.2f", resp.StoredOverallScore)
+		}
+	})
+
+	t.Run("Embedded in GetApplicant when requested", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return stored, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.GetApplicant(ctx, &applicantsv1.GetApplicantRequest{Id: 1})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if resp.Applicant.ScoreBreakdown != nil {
+			t.Error("Expected no breakdown unless requested")
+		}
+
+		resp, err = service.GetApplicant(ctx, &applicantsv1.GetApplicantRequest{Id: 1, IncludeScoreBreakdown: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if resp.Applicant.ScoreBreakdown == nil {
+			t.Fatal("Expected breakdown to be embedded")
+		}
+	})
+
+	t.Run("Applicant not found", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{}, sql.ErrNoRows
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		_, err := service.GetApplicantScoreExplanation(ctx, &applicantsv1.GetApplicantScoreExplanationRequest{Id: 999})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound, got %v", err)
+		}
+	})
+
+	t.Run("Validation failure - invalid ID", func(t *testing.T) {
+		service := &ApplicantService{
+			queries: &mockQuerier{},
+			logger:  logger,
+		}
+
+		_, err := service.GetApplicantScoreExplanation(ctx, &applicantsv1.GetApplicantScoreExplanationRequest{Id: 0})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument, got %v", err)
+		}
+	})
+}
+
+func TestRecomputeScores(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	// Five applicants; even IDs have an outdated stored score
+	var applicants []sqlc.Applicant
+	for id := int64(1); id <= 5; id++ {
+		app := sqlc.Applicant{
+			ID:                  id,
+			Name:                "Applicant",
+			Position:            "Developer",
+			YearsExperience:     3,
+			Skills:              []string{"Go"},
+			InterviewScore:      80.0,
+			CulturalFitScore:    80.0,
+			TechnicalScore:      80.0,
+			KnowsGo:             true,
+			ScoringModelVersion: scoring.BuiltinVersion,
+		}
+		app.OverallScore = scoring.DefaultModel().Score(scoringInput(&app))
+		if id%2 == 0 {
+			app.OverallScore = 1.0
+		}
+		applicants = append(applicants, app)
+	}
+
+	// listAfter serves applicants in ID order after the given checkpoint
+	listAfter := func(ctx context.Context, params sqlc.ListApplicantsForRescoreParams) ([]sqlc.Applicant, error) {
+		var rows []sqlc.Applicant
+		for _, app := range applicants {
+			if app.ID > params.AfterID && len(rows) < int(params.BatchSize) {
+				rows = append(rows, app)
+			}
+		}
+		return rows, nil
+	}
+
+	t.Run("Updates changed scores in batches", func(t *testing.T) {
+		var updated []int64
+		txCount := 0
+
+		mockQ := &mockQuerier{
+			rescoreListFunc: listAfter,
+			updateScoreFunc: func(ctx context.Context, params sqlc.UpdateApplicantScoreParams) (sqlc.Applicant, error) {
+				if params.ScoringModelVersion != scoring.BuiltinVersion {
+					t.Errorf("Expected version %s, got %s", scoring.BuiltinVersion, params.ScoringModelVersion)
+				}
+				updated = append(updated, params.ID)
+				return sqlc.Applicant{}, nil
+			},
+		}
+		mockQ.execTxFunc = func(ctx context.Context, fn func(q sqlc.Querier) error) error {
+			txCount++
+			return fn(mockQ)
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.RecomputeScores(ctx, &applicantsv1.RecomputeScoresRequest{BatchSize: 2})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if resp.Scanned != 5 || resp.Changed != 2 {
+			t.Errorf("Expected 5 scanned and 2 changed, got %d and %d", resp.Scanned, resp.Changed)
+		}
+		if len(updated) != 2 || updated[0] != 2 || updated[1] != 4 {
+			t.Errorf("Expected applicants 2 and 4 to be updated, got %v", updated)
+		}
+		if txCount != 3 {
+			t.Errorf("Expected 3 batch transactions, got %d", txCount)
+		}
+		if !resp.Done || resp.LastId != 5 {
+			t.Errorf("Expected done at last ID 5, got done=%v last_id=%d", resp.Done, resp.LastId)
+		}
+		if resp.Changes[0].OldScore != 1.0 || resp.Changes[0].NewScore != applicants[0].OverallScore {
+			t.Errorf("Unexpected change report: %+v", resp.Changes[0])
+		}
+	})
+
+	t.Run("Dry run writes nothing", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			previewFunc: func(ctx context.Context, params sqlc.PreviewApplicantsForRescoreParams) ([]sqlc.Applicant, error) {
+				return listAfter(ctx, sqlc.ListApplicantsForRescoreParams(params))
+			},
+			rescoreListFunc: func(ctx context.Context, params sqlc.ListApplicantsForRescoreParams) ([]sqlc.Applicant, error) {
+				t.Error("Expected dry run not to lock applicants")
+				return nil, nil
+			},
+			updateScoreFunc: func(ctx context.Context, params sqlc.UpdateApplicantScoreParams) (sqlc.Applicant, error) {
+				t.Errorf("Expected no update in dry run, got one for applicant %d", params.ID)
+				return sqlc.Applicant{}, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.RecomputeScores(ctx, &applicantsv1.RecomputeScoresRequest{DryRun: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if !resp.DryRun || resp.Changed != 2 {
+			t.Errorf("Expected dry run reporting 2 changes, got dry_run=%v changed=%d", resp.DryRun, resp.Changed)
+		}
+	})
+
+	t.Run("Reported changes are capped", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			previewFunc: func(ctx context.Context, params sqlc.PreviewApplicantsForRescoreParams) ([]sqlc.Applicant, error) {
+				// 1500 applicants, all with an outdated score
+				var rows []sqlc.Applicant
+				for id := params.AfterID + 1; id <= 1500 && len(rows) < int(params.BatchSize); id++ {
+					rows = append(rows, sqlc.Applicant{ID: id, Position: "Developer", OverallScore: -1})
+				}
+				return rows, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.RecomputeScores(ctx, &applicantsv1.RecomputeScoresRequest{DryRun: true, BatchSize: 400})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if resp.Changed != 1500 || !resp.Done {
+			t.Errorf("Expected all 1500 changes counted, got changed=%d done=%v", resp.Changed, resp.Done)
+		}
+		if len(resp.Changes) != maxReportedScoreChanges || !resp.ChangesTruncated {
+			t.Errorf("Expected %d reported changes and truncation, got %d truncated=%v", maxReportedScoreChanges, len(resp.Changes), resp.ChangesTruncated)
+		}
+	})
+
+	t.Run("Resumes after checkpoint and stops at max applicants", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			rescoreListFunc: listAfter,
+			updateScoreFunc: func(ctx context.Context, params sqlc.UpdateApplicantScoreParams) (sqlc.Applicant, error) {
+				return sqlc.Applicant{}, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.RecomputeScores(ctx, &applicantsv1.RecomputeScoresRequest{AfterId: 1, MaxApplicants: 2})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if resp.Scanned != 2 || resp.LastId != 3 || resp.Done {
+			t.Errorf("Expected 2 scanned up to ID 3 and not done, got scanned=%d last_id=%d done=%v", resp.Scanned, resp.LastId, resp.Done)
+		}
+	})
+
+	t.Run("Failed batch keeps last committed checkpoint", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			rescoreListFunc: listAfter,
+			updateScoreFunc: func(ctx context.Context, params sqlc.UpdateApplicantScoreParams) (sqlc.Applicant, error) {
+				if params.ID == 4 {
+					return sqlc.Applicant{}, errors.New("connection reset")
+				}
+				return sqlc.Applicant{}, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		_, err := service.RecomputeScores(ctx, &applicantsv1.RecomputeScoresRequest{BatchSize: 2})
+		if status.Code(err) != codes.Internal {
+			t.Fatalf("Expected Internal, got %v", err)
+		}
+		if !contains(err.Error(), "after id 2") {
+			t.Errorf("Expected error to name checkpoint 2, got %v", err)
+		}
+	})
+
+	t.Run("Validation failure - negative after_id", func(t *testing.T) {
+		service := &ApplicantService{
+			queries: &mockQuerier{},
+			logger:  logger,
+		}
+
+		_, err := service.RecomputeScores(ctx, &applicantsv1.RecomputeScoresRequest{AfterId: -1})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument, got %v", err)
+		}
+	})
+}
+
+func TestTransitionApplicantStatus(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	stored := sqlc.Applicant{
+		ID:     1,
+		Name:   "Jane Doe",
+		Status: int32(applicantsv1.ApplicantStatus_APPLICANT_STATUS_REVIEWING),
+	}
+
+	t.Run("Successful transition", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return stored, nil
+			},
+			statusFunc: func(ctx context.Context, params sqlc.UpdateApplicantStatusParams) (sqlc.Applicant, error) {
+				updated := stored
+				updated.Status = params.Status
+				return updated, nil
+			},
+			historyFunc: func(ctx context.Context, params sqlc.CreateStatusHistoryEntryParams) (sqlc.ApplicantStatusHistory, error) {
+				if params.ChangedBy != "recruiter@example.com" || params.Reason.String != "Strong take-home" {
+					t.Errorf("Expected who and why to be recorded, got %+v", params)
+				}
+				return sqlc.ApplicantStatusHistory{
+					ID:          10,
+					ApplicantID: params.ApplicantID,
+					FromStatus:  params.FromStatus,
+					ToStatus:    params.ToStatus,
+					ChangedBy:   params.ChangedBy,
+					Reason:      params.Reason,
+					ChangedAt:   time.Now(),
+				}, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		callerCtx := auth.NewContext(ctx, &auth.Principal{Subject: "recruiter@example.com", Roles: []string{"recruiter"}})
+		resp, err := service.TransitionApplicantStatus(callerCtx, &applicantsv1.TransitionApplicantStatusRequest{
+			Id:     1,
+			Status: applicantsv1.ApplicantStatus_APPLICANT_STATUS_INTERVIEWED,
+			Reason: "Strong take-home",
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if resp.Applicant.Status != applicantsv1.ApplicantStatus_APPLICANT_STATUS_INTERVIEWED {
+			t.Errorf("Expected status INTERVIEWED, got %v", resp.Applicant.Status)
+		}
+		if resp.Entry.FromStatus != applicantsv1.ApplicantStatus_APPLICANT_STATUS_REVIEWING {
+			t.Errorf("Expected from status REVIEWING, got %v", resp.Entry.FromStatus)
+		}
+	})
+
+	t.Run("Illegal transition", func(t *testing.T) {
+		rejected := stored
+		rejected.Status = int32(applicantsv1.ApplicantStatus_APPLICANT_STATUS_REJECTED)
+
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return rejected, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		_, err := service.TransitionApplicantStatus(ctx, &applicantsv1.TransitionApplicantStatusRequest{
+			Id:     1,
+			Status: applicantsv1.ApplicantStatus_APPLICANT_STATUS_APPLIED,
+		})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("Expected FailedPrecondition, got %v", err)
+		}
+	})
+
+	t.Run("Applicant not found", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{}, sql.ErrNoRows
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		_, err := service.TransitionApplicantStatus(ctx, &applicantsv1.TransitionApplicantStatusRequest{
+			Id:     999,
+			Status: applicantsv1.ApplicantStatus_APPLICANT_STATUS_REVIEWING,
+		})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound, got %v", err)
+		}
+	})
+
+	t.Run("Validation failure - unspecified status", func(t *testing.T) {
+		service := &ApplicantService{
+			queries: &mockQuerier{},
+			logger:  logger,
+		}
+
+		_, err := service.TransitionApplicantStatus(ctx, &applicantsv1.TransitionApplicantStatusRequest{Id: 1})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument, got %v", err)
+		}
+	})
+}
+
+func TestListStatusHistory(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	t.Run("Successful list", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{ID: id}, nil
+			},
+			listHistoryFunc: func(ctx context.Context, applicantID int64) ([]sqlc.ApplicantStatusHistory, error) {
+				return []sqlc.ApplicantStatusHistory{
+					{ID: 1, ApplicantID: applicantID, FromStatus: 1, ToStatus: 2, ChangedBy: "alice"},
+					{ID: 2, ApplicantID: applicantID, FromStatus: 2, ToStatus: 3, Reason: sql.NullString{String: "Great call", Valid: true}},
+				}, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.ListStatusHistory(ctx, &applicantsv1.ListStatusHistoryRequest{Id: 1})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if len(resp.Entries) != 2 {
+			t.Fatalf("Expected 2 entries, got %d", len(resp.Entries))
+		}
+		if resp.Entries[1].Reason != "Great call" || resp.Entries[0].ChangedBy != "alice" {
+			t.Errorf("Unexpected entries: %+v", resp.Entries)
+		}
+	})
+
+	t.Run("Applicant not found", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{}, sql.ErrNoRows
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		_, err := service.ListStatusHistory(ctx, &applicantsv1.ListStatusHistoryRequest{Id: 999})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound, got %v", err)
+		}
+	})
+}
+
+func TestApiKeys(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	t.Run("Create stores only the hash", func(t *testing.T) {
+		var stored sqlc.CreateApiKeyParams
+		mockQ := &mockQuerier{
+			createKeyFunc: func(ctx context.Context, params sqlc.CreateApiKeyParams) (sqlc.ApiKey, error) {
+				stored = params
+				return sqlc.ApiKey{ID: 1, Name: params.Name, Owner: params.Owner, KeyPrefix: params.KeyPrefix, KeyHash: params.KeyHash, Scopes: params.Scopes}, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.CreateApiKey(ctx, &applicantsv1.CreateApiKeyRequest{
+			Name:   "ATS nightly sync",
+			Owner:  "integrations",
+			Scopes: []string{"recruiter"},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if resp.Key == "" || stored.KeyHash != auth.HashAPIKey(resp.Key) {
+			t.Errorf("Expected stored hash to match the returned key")
+		}
+		if stored.KeyHash == resp.Key || !strings.HasPrefix(resp.Key, resp.ApiKey.KeyPrefix) {
+			t.Errorf("Expected prefix of the key to be stored, not the key itself")
+		}
+		if stored.ExpiresAt.Valid {
+			t.Errorf("Expected key without expiry")
+		}
+	})
+
+	t.Run("Create reports every invalid field", func(t *testing.T) {
+		service := &ApplicantService{
+			queries: &mockQuerier{},
+			logger:  logger,
+		}
+
+		_, err := service.CreateApiKey(ctx, &applicantsv1.CreateApiKeyRequest{
+			ExpiresAt: timestamppb.New(time.Now().Add(-time.Hour)),
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("Expected InvalidArgument, got %v", err)
+		}
+		for _, field := range []string{"name", "owner", "scopes", "expires_at"} {
+			if !contains(err.Error(), field) {
+				t.Errorf("Expected error to mention %s, got %v", field, err)
+			}
+		}
+	})
+
+	t.Run("Revoke unknown key", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			revokeKeyFunc: func(ctx context.Context, id int64) (sqlc.ApiKey, error) {
+				return sqlc.ApiKey{}, sql.ErrNoRows
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		_, err := service.RevokeApiKey(ctx, &applicantsv1.RevokeApiKeyRequest{Id: 42})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound, got %v", err)
+		}
+	})
+
+	t.Run("List omits secrets", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			listKeysFunc: func(ctx context.Context) ([]sqlc.ApiKey, error) {
+				return []sqlc.ApiKey{
+					{ID: 2, Name: "reporting", KeyPrefix: "ak_abcdefg", KeyHash: "secret-hash", RevokedAt: sql.NullTime{Time: time.Now(), Valid: true}},
+					{ID: 1, Name: "ats", KeyPrefix: "ak_hijklmn", KeyHash: "secret-hash"},
+				}, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.ListApiKeys(ctx, &applicantsv1.ListApiKeysRequest{})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if len(resp.ApiKeys) != 2 {
+			t.Fatalf("Expected 2 keys, got %d", len(resp.ApiKeys))
+		}
+		if resp.ApiKeys[0].RevokedAt == nil || resp.ApiKeys[1].RevokedAt != nil {
+			t.Errorf("Expected only the first key to be revoked")
+		}
+		if contains(resp.ApiKeys[0].String(), "secret-hash") {
+			t.Errorf("Expected key hash to be omitted")
+		}
+	})
+}
+
+func TestAuditLog(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	t.Run("Delete records actor, request ID and changes", func(t *testing.T) {
+		var event sqlc.CreateAuditEventParams
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{ID: id, Name: "Jane Doe", Version: 3}, nil
+			},
+			deleteFunc: func(ctx context.Context, id int64) (int64, error) {
+				return 1, nil
+			},
+			auditFunc: func(ctx context.Context, params sqlc.CreateAuditEventParams) (sqlc.ApplicantAuditLog, error) {
+				event = params
+				return sqlc.ApplicantAuditLog{}, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		reqCtx := auth.NewContext(ctx, &auth.Principal{Subject: "alice"})
+		reqCtx = requestid.NewContext(reqCtx, "req-123")
+		if _, err := service.DeleteApplicant(reqCtx, &applicantsv1.DeleteApplicantRequest{Id: 7, Etag: "*"}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if event.ApplicantID != 7 || event.Actor != "alice" || event.RequestID != "req-123" {
+			t.Errorf("Expected event for applicant 7 by alice in req-123, got %+v", event)
+		}
+		if event.Method != applicantsv1.ApplicantsService_DeleteApplicant_FullMethodName {
+			t.Errorf("Expected DeleteApplicant method, got %s", event.Method)
+		}
+		if string(event.Changes) != `{"deleted":{"before":false,"after":true}}` {
+			t.Errorf("Expected only the deleted flag to change, got %s", event.Changes)
+		}
+	})
+
+	t.Run("Failed audit write fails the mutation", func(t *testing.T) {
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				return sqlc.Applicant{ID: id}, nil
+			},
+			deleteFunc: func(ctx context.Context, id int64) (int64, error) {
+				return 1, nil
+			},
+			auditFunc: func(ctx context.Context, params sqlc.CreateAuditEventParams) (sqlc.ApplicantAuditLog, error) {
+				return sqlc.ApplicantAuditLog{}, errors.New("connection reset")
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		_, err := service.DeleteApplicant(ctx, &applicantsv1.DeleteApplicantRequest{Id: 7, Etag: "*"})
+		if status.Code(err) != codes.Internal {
+			t.Errorf("Expected Internal, got %v", err)
+		}
+	})
+
+	t.Run("Purge keeps no applicant data", func(t *testing.T) {
+		var event sqlc.CreateAuditEventParams
+		mockQ := &mockQuerier{
+			purgeFunc: func(ctx context.Context, id int64) (int64, error) {
+				return 1, nil
+			},
+			auditFunc: func(ctx context.Context, params sqlc.CreateAuditEventParams) (sqlc.ApplicantAuditLog, error) {
+				event = params
+				return sqlc.ApplicantAuditLog{}, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		if _, err := service.PurgeApplicant(ctx, &applicantsv1.PurgeApplicantRequest{Id: 7}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if event.Actor != "system" || string(event.Changes) != "{}" {
+			t.Errorf("Expected an empty system event, got actor %q and changes %s", event.Actor, event.Changes)
+		}
+	})
+
+	t.Run("Sensitive values are masked", func(t *testing.T) {
+		before := &sqlc.Applicant{ID: 7, Name: "Jane", Email: "jane@example.com"}
+		after := &sqlc.Applicant{ID: 7, Name: "Janet", Email: "janet@example.com", SalaryExpectation: sql.NullString{String: "100k", Valid: true}}
+
+		changes, err := auditChanges(before, after)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		var fields map[string]fieldChange
+		if err := json.Unmarshal(changes, &fields); err != nil {
+			t.Fatalf("Failed to decode changes: %v", err)
+		}
+		if fields["name"].Before != "Jane" || fields["name"].After != "Janet" {
+			t.Errorf("Expected name values to be kept, got %+v", fields["name"])
+		}
+		if fields["email"].Before != logging.Redacted || fields["email"].After != logging.Redacted {
+			t.Errorf("Expected email values to be masked, got %+v", fields["email"])
+		}
+		if fields["salary_expectation"].Before != "" || fields["salary_expectation"].After != logging.Redacted {
+			t.Errorf("Expected salary to be masked once set, got %+v", fields["salary_expectation"])
+		}
+		if contains(string(changes), "example.com") || contains(string(changes), "100k") {
+			t.Errorf("Expected no sensitive values in changes, got %s", changes)
+		}
+	})
+
+	t.Run("List pages through events", func(t *testing.T) {
+		var params sqlc.ListAuditEventsParams
+		mockQ := &mockQuerier{
+			listAuditFunc: func(ctx context.Context, p sqlc.ListAuditEventsParams) ([]sqlc.ApplicantAuditLog, error) {
+				params = p
+				return []sqlc.ApplicantAuditLog{
+					{ID: 9, ApplicantID: 7, Actor: "alice", CreatedAt: time.Now(), Changes: json.RawMessage(`{"name":{"before":"Jane","after":"Janet"}}`)},
+					{ID: 8, ApplicantID: 7, Actor: "alice", CreatedAt: time.Now(), Changes: json.RawMessage(`{}`)},
+				}, nil
+			},
+		}
+
+		service := &ApplicantService{
+			queries: mockQ,
+			logger:  logger,
+		}
+
+		resp, err := service.ListAuditEvents(ctx, &applicantsv1.ListAuditEventsRequest{ApplicantId: 7, Limit: 1})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if params.PageSize != 2 || params.ApplicantID != 7 {
+			t.Errorf("Expected one extra event to be fetched for applicant 7, got %+v", params)
+		}
+		if len(resp.Events) != 1 || resp.Events[0].Id != 9 {
+			t.Fatalf("Expected only event 9, got %v", resp.Events)
+		}
+		if got := resp.Events[0].Changes.Fields["name"].GetStructValue().Fields["after"].GetStringValue(); got != "Janet" {
+			t.Errorf("Expected name change to Janet, got %q", got)
+		}
+
+		if _, err := service.ListAuditEvents(ctx, &applicantsv1.ListAuditEventsRequest{PageToken: resp.NextPageToken}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if params.BeforeID != 9 {
+			t.Errorf("Expected next page to start before event 9, got %d", params.BeforeID)
+		}
+	})
+
+	t.Run("List validation failure", func(t *testing.T) {
+		service := &ApplicantService{
+			queries: &mockQuerier{},
+			logger:  logger,
+		}
+
+		now := time.Now()
+		_, err := service.ListAuditEvents(ctx, &applicantsv1.ListAuditEventsRequest{
+			StartTime: timestamppb.New(now),
+			EndTime:   timestamppb.New(now.Add(-time.Hour)),
+			PageToken: "not-a-token",
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("Expected InvalidArgument, got %v", err)
+		}
+		for _, field := range []string{"end_time", "page_token"} {
+			if !contains(err.Error(), field) {
+				t.Errorf("Expected error to mention %s, got %v", field, err)
+			}
+		}
+	})
+
+	t.Run("ListApplicants page token is rejected", func(t *testing.T) {
+		service := &ApplicantService{
+			queries: &mockQuerier{},
+			logger:  logger,
+		}
+
+		token := util.EncodePageToken(util.PageCursor{OverallScore: 80, CreatedAt: time.Now(), ID: 9}, util.ListFilters{})
+		_, err := service.ListAuditEvents(ctx, &applicantsv1.ListAuditEventsRequest{PageToken: token})
+		if status.Code(err) != codes.InvalidArgument || !contains(err.Error(), "page_token") {
+			t.Errorf("Expected InvalidArgument for page_token, got %v", err)
+		}
+	})
+}
+
 // Helper function to check if string contains any of the substrings
 func containsAny(s string, substrs []string) bool {
 	for _, substr := range substrs {
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+	"github.com/Thrun12/golang-assignment/internal/util"
+)
+
+// TransitionApplicantStatus moves an applicant to a new status and records the change
+func (s *ApplicantService) TransitionApplicantStatus(ctx context.Context, req *applicantsv1.TransitionApplicantStatusRequest) (*applicantsv1.TransitionApplicantStatusResponse, error) {
+	// Validate input
+	verr := &util.ValidationError{}
+	if req.Id <= 0 {
+		verr.Add("id", "id must be positive")
+	}
+	if !isKnownStatus(req.Status) {
+		verr.Add("status", "status must be a valid applicant status")
+	}
+	if len(req.Reason) > 1000 {
+		verr.Add("reason", "reason must be at most 1000 characters")
+	}
+	if err := verr.Err(); err != nil {
+		return nil, invalidRequest(err)
+	}
+
+	s.log(ctx).Debug("transitioning applicant status",
+		zap.Int64("id", req.Id),
+		zap.String("status", req.Status.String()),
+	)
+
+	var applicant sqlc.Applicant
+	var entry sqlc.ApplicantStatusHistory
+	err := s.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		existing, err := q.GetApplicantForUpdate(ctx, req.Id)
+		if err != nil {
+			return err
+		}
+
+		from := applicantsv1.ApplicantStatus(existing.Status)
+		if err := checkStatusTransition(from, req.Status); err != nil {
+			return err
+		}
+
+		applicant, err = q.UpdateApplicantStatus(ctx, sqlc.UpdateApplicantStatusParams{
+			ID:     req.Id,
+			Status: int32(req.Status),
+		})
+		if err != nil {
+			return err
+		}
+
+		entry, err = recordStatusChange(ctx, q, req.Id, from, req.Status, req.Reason)
+		if err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, q, applicantsv1.ApplicantsService_TransitionApplicantStatus_FullMethodName, req.Id, &existing, &applicant)
+	})
+	if err != nil {
+		return nil, s.translateError(ctx, err, "transition applicant status", applicantResource(req.Id))
+	}
+
+	return &applicantsv1.TransitionApplicantStatusResponse{
+		Applicant: util.DbApplicantToProto(&applicant),
+		Entry:     util.StatusHistoryEntryToProto(&entry),
+	}, nil
+}
+
+// ListStatusHistory lists the status changes of an applicant, oldest first
+func (s *ApplicantService) ListStatusHistory(ctx context.Context, req *applicantsv1.ListStatusHistoryRequest) (*applicantsv1.ListStatusHistoryResponse, error) {
+	// Validate input
+	if req.Id <= 0 {
+		return nil, invalidField("id", "id must be positive")
+	}
+
+	s.log(ctx).Debug("listing status history", zap.Int64("id", req.Id))
+
+	if _, err := s.queries.GetApplicant(ctx, req.Id); err != nil {
+		return nil, s.translateError(ctx, err, "get applicant", applicantResource(req.Id))
+	}
+
+	history, err := s.queries.ListStatusHistory(ctx, req.Id)
+	if err != nil {
+		return nil, s.translateError(ctx, err, "list status history", applicantResource(req.Id))
+	}
+
+	entries := make([]*applicantsv1.StatusHistoryEntry, len(history))
+	for i := range history {
+		entries[i] = util.StatusHistoryEntryToProto(&history[i])
+	}
+
+	return &applicantsv1.ListStatusHistoryResponse{
+		Entries: entries,
+	}, nil
+}
+
+// recordStatusChange adds a status history entry for an applicant, attributed
+// to the caller like audit events
+func recordStatusChange(ctx context.Context, q sqlc.Querier, applicantID int64, from, to applicantsv1.ApplicantStatus, reason string) (sqlc.ApplicantStatusHistory, error) {
+	var reasonPtr *string
+	if reason != "" {
+		reasonPtr = &reason
+	}
+
+	return q.CreateStatusHistoryEntry(ctx, sqlc.CreateStatusHistoryEntryParams{
+		ApplicantID: applicantID,
+		FromStatus:  int32(from),
+		ToStatus:    int32(to),
+		ChangedBy:   auditActor(ctx),
+		Reason:      util.ToNullString(reasonPtr),
+	})
+}
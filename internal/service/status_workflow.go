@@ -0,0 +1,53 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+)
+
+// errInvalidStatusTransition is returned when a status change is not allowed by the workflow
+var errInvalidStatusTransition = errors.New("invalid status transition")
+
+// statusTransitions lists the statuses an applicant may move to from each status.
+// Rejected applicants can be reconsidered; hired applicants are final.
+var statusTransitions = map[applicantsv1.ApplicantStatus][]applicantsv1.ApplicantStatus{
+	applicantsv1.ApplicantStatus_APPLICANT_STATUS_APPLIED: {
+		applicantsv1.ApplicantStatus_APPLICANT_STATUS_REVIEWING,
+		applicantsv1.ApplicantStatus_APPLICANT_STATUS_REJECTED,
+	},
+	applicantsv1.ApplicantStatus_APPLICANT_STATUS_REVIEWING: {
+		applicantsv1.ApplicantStatus_APPLICANT_STATUS_INTERVIEWED,
+		applicantsv1.ApplicantStatus_APPLICANT_STATUS_REJECTED,
+	},
+	applicantsv1.ApplicantStatus_APPLICANT_STATUS_INTERVIEWED: {
+		applicantsv1.ApplicantStatus_APPLICANT_STATUS_HIRED,
+		applicantsv1.ApplicantStatus_APPLICANT_STATUS_REJECTED,
+		applicantsv1.ApplicantStatus_APPLICANT_STATUS_OBVIOUSLY_THE_BEST,
+	},
+	applicantsv1.ApplicantStatus_APPLICANT_STATUS_OBVIOUSLY_THE_BEST: {
+		applicantsv1.ApplicantStatus_APPLICANT_STATUS_HIRED,
+	},
+	applicantsv1.ApplicantStatus_APPLICANT_STATUS_REJECTED: {
+		applicantsv1.ApplicantStatus_APPLICANT_STATUS_REVIEWING,
+	},
+	applicantsv1.ApplicantStatus_APPLICANT_STATUS_HIRED: {},
+}
+
+// checkStatusTransition returns an error wrapping errInvalidStatusTransition
+// unless the workflow allows moving from one status to the other
+func checkStatusTransition(from, to applicantsv1.ApplicantStatus) error {
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w from %s to %s", errInvalidStatusTransition, from, to)
+}
+
+// isKnownStatus reports whether status is a defined, non-unspecified status
+func isKnownStatus(status applicantsv1.ApplicantStatus) bool {
+	_, ok := statusTransitions[status]
+	return ok
+}
@@ -2,87 +2,251 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 
-	"github.com/lib/pq"
 	"go.uber.org/zap"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
 
 	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
 	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+	"github.com/Thrun12/golang-assignment/internal/scoring"
 	"github.com/Thrun12/golang-assignment/internal/util"
 )
 
-// UpdateApplicant updates an existing applicant and recalculates score
+// scoringFields lists the update_mask paths that feed into the overall score
+var scoringFields = map[string]bool{
+	"name":                 true,
+	"position":             true,
+	"skills":               true,
+	"years_experience":     true,
+	"interview_score":      true,
+	"cultural_fit_score":   true,
+	"technical_score":      true,
+	"can_exit_vim":         true,
+	"knows_go":             true,
+	"debugs_in_production": true,
+}
+
+// UpdateApplicant updates an existing applicant and recalculates score.
+// When update_mask is set, only the masked fields are changed and the score is
+// only recalculated if one of its inputs is part of the mask. Status changes
+// must follow the status workflow and are recorded in the status history; an
+// unspecified status keeps the current one. The request must carry the
+// applicant's current etag so concurrent edits are not silently overwritten.
 func (s *ApplicantService) UpdateApplicant(ctx context.Context, req *applicantsv1.UpdateApplicantRequest) (*applicantsv1.UpdateApplicantResponse, error) {
+	paths := req.GetUpdateMask().GetPaths()
+	masked := len(paths) > 0
+	etag := requestETag(ctx, req.Etag)
+
 	// Validate input
-	if err := util.ValidateApplicant(req.Name, req.Email, req.Position, req.YearsExperience, req.GithubStars, req.InterviewScore, req.CulturalFitScore, req.TechnicalScore, true, req.Id); err != nil {
-		s.logger.Debug("validation failed", zap.Error(err))
-		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	if err := requireETag(etag); err != nil {
+		return nil, err
+	}
+	if masked {
+		if err := validateUpdateMask(req); err != nil {
+			s.log(ctx).Debug("invalid update mask", zap.Strings("paths", paths), zap.Error(err))
+			return nil, invalidField("update_mask", fmt.Sprintf("update_mask is invalid: %v", err))
+		}
+	} else if err := s.validateUpdate(ctx, req); err != nil {
+		return nil, err
 	}
 
-	s.logger.Debug("updating applicant", zap.Int64("id", req.Id))
-
-	// Recalculate overall score
-	overallScore := util.CalculateOverallScore(
-		req.Name,
-		req.Skills,
-		req.YearsExperience,
-		req.InterviewScore,
-		req.CulturalFitScore,
-		req.TechnicalScore,
-		req.CanExitVim,
-		req.KnowsGo,
-		req.DebugsInProduction,
+	s.log(ctx).Debug("updating applicant",
+		zap.Int64("id", req.Id),
+		zap.Strings("update_mask", paths),
 	)
 
-	// Prepare optional fields
-	var funFact, availability, salaryExpectation *string
-	if req.FunFact != "" {
-		funFact = &req.FunFact
-	}
-	if req.Availability != "" {
-		availability = &req.Availability
-	}
-	if req.SalaryExpectation != "" {
-		salaryExpectation = &req.SalaryExpectation
-	}
+	var applicant sqlc.Applicant
+	err := s.queries.ExecTx(ctx, func(q sqlc.Querier) error {
+		existing, err := q.GetApplicantForUpdate(ctx, req.Id)
+		if err != nil {
+			return err
+		}
+		if err := checkETag(&existing, etag); err != nil {
+			return err
+		}
 
-	applicant, err := s.queries.UpdateApplicant(ctx, sqlc.UpdateApplicantParams{
-		ID:                 req.Id,
-		Name:               req.Name,
-		Email:              req.Email,
-		Position:           req.Position,
-		YearsExperience:    req.YearsExperience,
-		Skills:             req.Skills,
-		GithubStars:        req.GithubStars,
-		CanExitVim:         req.CanExitVim,
-		KnowsGo:            req.KnowsGo,
-		DebugsInProduction: req.DebugsInProduction,
-		InterviewScore:     req.InterviewScore,
-		CulturalFitScore:   req.CulturalFitScore,
-		TechnicalScore:     req.TechnicalScore,
-		OverallScore:       overallScore,
-		Status:             int32(req.Status),
-		FunFact:            util.ToNullString(funFact),
-		Availability:       util.ToNullString(availability),
-		SalaryExpectation:  util.ToNullString(salaryExpectation),
-	})
+		recalculate := true
+		if masked {
+			req, err = applyUpdateMask(req, &existing)
+			if err != nil {
+				return err
+			}
+			if err := s.validateUpdate(ctx, req); err != nil {
+				return err
+			}
+			recalculate = touchesScoring(paths)
+		}
 
-	if err != nil {
-		// Check for unique constraint violation on email
-		if pqErr, ok := err.(*pq.Error); ok {
-			if pqErr.Code == "23505" && strings.Contains(pqErr.Constraint, "email") {
-				s.logger.Error("email already exists", zap.String("email", req.Email))
-				return nil, status.Errorf(codes.AlreadyExists, "email address already exists: %s", req.Email)
+		// Enforce the status workflow
+		fromStatus := applicantsv1.ApplicantStatus(existing.Status)
+		toStatus := req.Status
+		if toStatus == applicantsv1.ApplicantStatus_APPLICANT_STATUS_UNSPECIFIED {
+			toStatus = fromStatus
+		}
+		if toStatus != fromStatus {
+			if err := checkStatusTransition(fromStatus, toStatus); err != nil {
+				return err
 			}
 		}
-		s.logger.Error("failed to update applicant", zap.Int64("id", req.Id), zap.Error(err))
-		return nil, status.Errorf(codes.Internal, "failed to update applicant: %v", err)
+
+		// Recalculate overall score
+		overallScore := existing.OverallScore
+		modelVersion := existing.ScoringModelVersion
+		if recalculate {
+			model := s.scoringModels.ForPosition(req.Position)
+			overallScore = model.Score(scoring.Input{
+				Name:               req.Name,
+				Position:           req.Position,
+				Skills:             req.Skills,
+				YearsExperience:    req.YearsExperience,
+				InterviewScore:     req.InterviewScore,
+				CulturalFitScore:   req.CulturalFitScore,
+				TechnicalScore:     req.TechnicalScore,
+				CanExitVim:         req.CanExitVim,
+				KnowsGo:            req.KnowsGo,
+				DebugsInProduction: req.DebugsInProduction,
+			})
+			modelVersion = model.Version
+		}
+
+		// Prepare optional fields
+		var funFact, availability, salaryExpectation *string
+		if req.FunFact != "" {
+			funFact = &req.FunFact
+		}
+		if req.Availability != "" {
+			availability = &req.Availability
+		}
+		if req.SalaryExpectation != "" {
+			salaryExpectation = &req.SalaryExpectation
+		}
+
+		applicant, err = q.UpdateApplicant(ctx, sqlc.UpdateApplicantParams{
+			ID:                  req.Id,
+			Name:                req.Name,
+			Email:               req.Email,
+			Position:            req.Position,
+			YearsExperience:     req.YearsExperience,
+			Skills:              req.Skills,
+			GithubStars:         req.GithubStars,
+			CanExitVim:          req.CanExitVim,
+			KnowsGo:             req.KnowsGo,
+			DebugsInProduction:  req.DebugsInProduction,
+			InterviewScore:      req.InterviewScore,
+			CulturalFitScore:    req.CulturalFitScore,
+			TechnicalScore:      req.TechnicalScore,
+			OverallScore:        overallScore,
+			Status:              int32(toStatus),
+			FunFact:             util.ToNullString(funFact),
+			Availability:        util.ToNullString(availability),
+			SalaryExpectation:   util.ToNullString(salaryExpectation),
+			ScoringModelVersion: modelVersion,
+		})
+		if err != nil {
+			return err
+		}
+
+		if toStatus != fromStatus {
+			if _, err := recordStatusChange(ctx, q, req.Id, fromStatus, toStatus, ""); err != nil {
+				return err
+			}
+		}
+		return s.recordAudit(ctx, q, applicantsv1.ApplicantsService_UpdateApplicant_FullMethodName, req.Id, &existing, &applicant)
+	})
+
+	if err != nil {
+		return nil, s.translateError(ctx, err, "update applicant", applicantResource(req.Id))
 	}
 
 	return &applicantsv1.UpdateApplicantResponse{
 		Applicant: util.DbApplicantToProto(&applicant),
 	}, nil
 }
+
+// validateUpdate checks the rules of a full update request that depend on the
+// update_mask; field formats are enforced by the ValidationInterceptor
+func (s *ApplicantService) validateUpdate(ctx context.Context, req *applicantsv1.UpdateApplicantRequest) error {
+	verr := &util.ValidationError{}
+	if err := util.ValidateRequiredApplicantFields(req.Name, req.Email, req.Position); err != nil {
+		errors.As(err, &verr)
+	}
+	if req.Status != applicantsv1.ApplicantStatus_APPLICANT_STATUS_UNSPECIFIED && !isKnownStatus(req.Status) {
+		verr.Add("status", fmt.Sprintf("status %d is not a valid applicant status", req.Status))
+	}
+	if err := verr.Err(); err != nil {
+		s.log(ctx).Debug("validation failed", zap.Error(err))
+		return invalidRequest(err)
+	}
+	return nil
+}
+
+// validateUpdateMask checks that every mask path names an updatable top-level
+// field
+func validateUpdateMask(req *applicantsv1.UpdateApplicantRequest) error {
+	fields := req.ProtoReflect().Descriptor().Fields()
+	for _, path := range req.UpdateMask.GetPaths() {
+		switch {
+		case strings.Contains(path, "."):
+			return errors.New(path + " is nested, only top-level fields can be updated")
+		case fields.ByName(protoreflect.Name(path)) == nil:
+			return errors.New("unknown field " + path + " in mask")
+		case path == "id" || path == "update_mask" || path == "etag":
+			return errors.New(path + " cannot be updated")
+		}
+	}
+	return nil
+}
+
+// applyUpdateMask returns a full update request built from the stored applicant,
+// with the masked fields taken from req
+func applyUpdateMask(req *applicantsv1.UpdateApplicantRequest, existing *sqlc.Applicant) (*applicantsv1.UpdateApplicantRequest, error) {
+	merged := &applicantsv1.UpdateApplicantRequest{
+		Id:                 existing.ID,
+		Name:               existing.Name,
+		Email:              existing.Email,
+		Position:           existing.Position,
+		YearsExperience:    existing.YearsExperience,
+		Skills:             existing.Skills,
+		GithubStars:        existing.GithubStars,
+		CanExitVim:         existing.CanExitVim,
+		KnowsGo:            existing.KnowsGo,
+		DebugsInProduction: existing.DebugsInProduction,
+		InterviewScore:     existing.InterviewScore,
+		CulturalFitScore:   existing.CulturalFitScore,
+		TechnicalScore:     existing.TechnicalScore,
+		Status:             applicantsv1.ApplicantStatus(existing.Status),
+		FunFact:            util.NullStringToString(existing.FunFact),
+		Availability:       util.NullStringToString(existing.Availability),
+		SalaryExpectation:  util.NullStringToString(existing.SalaryExpectation),
+	}
+
+	src := req.ProtoReflect()
+	dst := merged.ProtoReflect()
+	fields := src.Descriptor().Fields()
+	for _, path := range req.UpdateMask.GetPaths() {
+		fd := fields.ByName(protoreflect.Name(path))
+		if fd == nil {
+			return nil, invalidField("update_mask", "update_mask is invalid: unknown field "+path+" in mask")
+		}
+		if src.Has(fd) {
+			dst.Set(fd, src.Get(fd))
+		} else {
+			dst.Clear(fd)
+		}
+	}
+
+	return merged, nil
+}
+
+// touchesScoring reports whether any mask path is an input to the overall score
+func touchesScoring(paths []string) bool {
+	for _, path := range paths {
+		if scoringFields[path] {
+			return true
+		}
+	}
+	return false
+}
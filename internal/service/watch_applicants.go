@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/db"
+	"github.com/Thrun12/golang-assignment/internal/util"
+)
+
+// changeTypes maps the changes announced by the database to event types
+var changeTypes = map[string]applicantsv1.ApplicantEventType{
+	db.ChangeCreated: applicantsv1.ApplicantEventType_APPLICANT_EVENT_TYPE_CREATED,
+	db.ChangeUpdated: applicantsv1.ApplicantEventType_APPLICANT_EVENT_TYPE_UPDATED,
+	db.ChangeDeleted: applicantsv1.ApplicantEventType_APPLICANT_EVENT_TYPE_DELETED,
+}
+
+// WatchApplicants streams applicant changes as they are committed until the
+// client disconnects or the subscription ends
+func (s *ApplicantService) WatchApplicants(req *applicantsv1.WatchApplicantsRequest, stream applicantsv1.ApplicantsService_WatchApplicantsServer) error {
+	ctx := stream.Context()
+	if s.changes == nil {
+		return status.Error(codes.Unimplemented, "watching applicants is not available")
+	}
+
+	sub := s.changes.Subscribe()
+	defer sub.Close()
+
+	// Send the headers right away so clients know the stream is open before
+	// the first change
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	s.log(ctx).Debug("watching applicants")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case change, ok := <-sub.Changes():
+			if !ok {
+				return watchEnded(sub.Err())
+			}
+
+			event := s.changeEvent(ctx, change)
+			if event == nil {
+				continue
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// changeEvent builds the event of change from the applicant the change feed
+// loaded with it. It returns nil for changes of an unknown type.
+func (s *ApplicantService) changeEvent(ctx context.Context, change db.ApplicantChange) *applicantsv1.WatchApplicantsResponse {
+	eventType, ok := changeTypes[change.Type]
+	if !ok {
+		s.log(ctx).Warn("unknown applicant change", zap.String("type", change.Type))
+		return nil
+	}
+
+	event := &applicantsv1.WatchApplicantsResponse{
+		Type:        eventType,
+		ApplicantId: change.ApplicantID,
+		OccurredAt:  timestamppb.New(change.At),
+	}
+	if change.Applicant != nil {
+		event.Applicant = util.DbApplicantToProto(change.Applicant)
+	}
+	return event
+}
+
+// watchEnded converts the reason a subscription ended into the status the
+// stream ends with. Clients should reload with ListApplicants and watch again.
+func watchEnded(err error) error {
+	switch {
+	case errors.Is(err, db.ErrChangesMissed):
+		return status.Error(codes.Unavailable, "changes may have been missed, reload applicants and watch again")
+	case errors.Is(err, db.ErrSubscriberTooSlow):
+		return status.Error(codes.Unavailable, "client is not keeping up with changes, reload applicants and watch again")
+	case errors.Is(err, db.ErrChangeNotLoaded):
+		return status.Error(codes.Unavailable, "a change could not be loaded, reload applicants and watch again")
+	default:
+		return status.Error(codes.Unavailable, "server is shutting down, watch again")
+	}
+}
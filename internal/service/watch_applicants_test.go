@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
+	"github.com/Thrun12/golang-assignment/internal/db"
+	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+)
+
+// watchStream is a WatchApplicants stream that hands sent events to the test
+type watchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	opened chan struct{}
+	events chan *applicantsv1.WatchApplicantsResponse
+}
+
+func newWatchStream(ctx context.Context) *watchStream {
+	return &watchStream{
+		ctx:    ctx,
+		opened: make(chan struct{}),
+		events: make(chan *applicantsv1.WatchApplicantsResponse, 10),
+	}
+}
+
+func (s *watchStream) Context() context.Context { return s.ctx }
+
+func (s *watchStream) SendHeader(metadata.MD) error {
+	close(s.opened)
+	return nil
+}
+
+func (s *watchStream) Send(event *applicantsv1.WatchApplicantsResponse) error {
+	s.events <- event
+	return nil
+}
+
+// next waits for the next event sent on s
+func (s *watchStream) next(t *testing.T) *applicantsv1.WatchApplicantsResponse {
+	t.Helper()
+	select {
+	case event := <-s.events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for an event")
+		return nil
+	}
+}
+
+// watch runs WatchApplicants on stream once it is opened, returning the
+// channel its result is delivered on
+func watch(t *testing.T, service *ApplicantService, stream *watchStream) <-chan error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() {
+		done <- service.WatchApplicants(&applicantsv1.WatchApplicantsRequest{}, stream)
+	}()
+
+	select {
+	case <-stream.opened:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the stream to open")
+	}
+	return done
+}
+
+func TestWatchApplicants(t *testing.T) {
+	logger := zap.NewNop()
+
+	t.Run("Streams changes until the client leaves", func(t *testing.T) {
+		notifications := make(chan *pq.Notification)
+		defer close(notifications)
+
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				if id == 3 {
+					return sqlc.Applicant{}, sql.ErrNoRows
+				}
+				return sqlc.Applicant{ID: id, Name: "Jane Doe", Position: "Developer"}, nil
+			},
+		}
+		service := &ApplicantService{
+			queries: mockQ,
+			changes: db.NewChangeFeed(notifications, mockQ, logger),
+			logger:  logger,
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stream := newWatchStream(ctx)
+		done := watch(t, service, stream)
+
+		notifications <- &pq.Notification{Extra: `{"type":"CREATED","id":1,"at":"2024-06-01T12:00:00Z"}`}
+
+		created := stream.next(t)
+		if created.Type != applicantsv1.ApplicantEventType_APPLICANT_EVENT_TYPE_CREATED || created.Applicant.GetName() != "Jane Doe" {
+			t.Errorf("Unexpected created event %v", created)
+		}
+		if created.OccurredAt.AsTime().Second() != 0 {
+			t.Errorf("Expected the time of the change, got %v", created.OccurredAt.AsTime())
+		}
+
+		notifications <- &pq.Notification{Extra: `{"type":"DELETED","id":2,"at":"2024-06-01T12:00:01Z"}`}
+		// Deleted again before it could be loaded
+		notifications <- &pq.Notification{Extra: `{"type":"UPDATED","id":3,"at":"2024-06-01T12:00:02Z"}`}
+		notifications <- &pq.Notification{Extra: `{"type":"UPDATED","id":1,"at":"2024-06-01T12:00:03Z"}`}
+
+		deleted := stream.next(t)
+		if deleted.Type != applicantsv1.ApplicantEventType_APPLICANT_EVENT_TYPE_DELETED || deleted.ApplicantId != 2 || deleted.Applicant != nil {
+			t.Errorf("Unexpected deleted event %v", deleted)
+		}
+
+		updated := stream.next(t)
+		if updated.Type != applicantsv1.ApplicantEventType_APPLICANT_EVENT_TYPE_UPDATED || updated.ApplicantId != 1 {
+			t.Errorf("Expected the update of applicant 1 after skipping applicant 3, got %v", updated)
+		}
+
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Expected no error after the client left, got: %v", err)
+		}
+	})
+
+	t.Run("Missed changes end the stream", func(t *testing.T) {
+		notifications := make(chan *pq.Notification)
+		defer close(notifications)
+
+		mockQ := &mockQuerier{}
+		service := &ApplicantService{
+			queries: mockQ,
+			changes: db.NewChangeFeed(notifications, mockQ, logger),
+			logger:  logger,
+		}
+
+		done := watch(t, service, newWatchStream(context.Background()))
+		notifications <- nil
+
+		if err := <-done; status.Code(err) != codes.Unavailable {
+			t.Errorf("Expected Unavailable, got %v", err)
+		}
+	})
+
+	t.Run("A burst of changes reaches every watcher", func(t *testing.T) {
+		notifications := make(chan *pq.Notification)
+		defer close(notifications)
+
+		var loads atomic.Int64
+		mockQ := &mockQuerier{
+			getFunc: func(ctx context.Context, id int64) (sqlc.Applicant, error) {
+				loads.Add(1)
+				return sqlc.Applicant{ID: id, Name: "Jane Doe", Position: "Developer"}, nil
+			},
+		}
+		service := &ApplicantService{
+			queries: mockQ,
+			changes: db.NewChangeFeed(notifications, mockQ, logger),
+			logger:  logger,
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var streams []*watchStream
+		for i := 0; i < 3; i++ {
+			stream := newWatchStream(ctx)
+			watch(t, service, stream)
+			streams = append(streams, stream)
+		}
+
+		// More changes than a watcher may fall behind by, to a few applicants
+		start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+		changes := 600
+		var last time.Time
+		for i := 0; i < changes; i++ {
+			last = start.Add(time.Duration(i) * time.Second)
+			notifications <- &pq.Notification{Extra: fmt.Sprintf(`{"type":"UPDATED","id":%d,"at":%q}`, i%3+1, last.Format(time.RFC3339))}
+		}
+
+		for _, stream := range streams {
+			for {
+				event := stream.next(t)
+				if event.Applicant.GetName() != "Jane Doe" {
+					t.Errorf("Expected the changed applicant, got %v", event)
+				}
+				if event.OccurredAt.AsTime().Equal(last) {
+					break
+				}
+			}
+		}
+		if n := loads.Load(); n != int64(changes) {
+			t.Errorf("Expected each change to be loaded once for all watchers, got %d loads for %d changes", n, changes)
+		}
+	})
+
+	t.Run("Without a change feed", func(t *testing.T) {
+		service := &ApplicantService{
+			queries: &mockQuerier{},
+			logger:  logger,
+		}
+
+		err := service.WatchApplicants(&applicantsv1.WatchApplicantsRequest{}, newWatchStream(context.Background()))
+		if status.Code(err) != codes.Unimplemented {
+			t.Errorf("Expected Unimplemented, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// ServiceName identifies the service in exported spans
+const ServiceName = "applicants-api"
+
+// Exporters selectable in Config
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+// Config selects where spans are exported to
+type Config struct {
+	// Exporter is one of ExporterNone, ExporterStdout, ExporterFile or ExporterOTLP
+	Exporter string
+
+	// File receives spans as JSON lines with ExporterFile
+	File string
+
+	// OTLPEndpoint is the host:port of the OTLP/gRPC collector with ExporterOTLP
+	OTLPEndpoint string
+
+	// OTLPInsecure disables TLS towards the collector
+	OTLPInsecure bool
+
+	// SampleRatio is the fraction of new traces recorded; traces started by a
+	// caller follow the caller's sampling decision
+	SampleRatio float64
+
+	// ServiceVersion and Environment are recorded on every span
+	ServiceVersion string
+	Environment    string
+}
+
+// Setup installs the global tracer provider and the W3C trace context
+// propagator. The returned function flushes pending spans and must be called
+// on shutdown. With ExporterNone, trace context is still propagated but no
+// spans are recorded.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	exporter, closer, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(ServiceName),
+		semconv.ServiceVersion(cfg.ServiceVersion),
+		semconv.DeploymentEnvironment(cfg.Environment),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			if cerr := closer.Close(); err == nil {
+				err = cerr
+			}
+		}
+		return err
+	}, nil
+}
+
+// newExporter creates the span exporter selected by cfg, and the file it
+// writes to if any
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, io.Closer, error) {
+	switch cfg.Exporter {
+	case ExporterNone, "":
+		return nil, nil, nil
+
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create stdout trace exporter: %w", err)
+		}
+		return exporter, nil, nil
+
+	case ExporterFile:
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, nil, fmt.Errorf("failed to create file trace exporter: %w", err)
+		}
+		return exporter, f, nil
+
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint)}
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err := otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+		}
+		return exporter, nil, nil
+	}
+
+	return nil, nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+}
@@ -0,0 +1,58 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.json")
+	shutdown, err := Setup(context.Background(), Config{
+		Exporter:       ExporterFile,
+		File:           path,
+		SampleRatio:    1,
+		ServiceVersion: "test",
+		Environment:    "test",
+	})
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	_, span := otel.Tracer("test").Start(context.Background(), "GetApplicant")
+	span.End()
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read trace file: %v", err)
+	}
+	for _, expected := range []string{`"Name":"GetApplicant"`, ServiceName} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected %s in trace file:\n%s", expected, data)
+		}
+	}
+}
+
+func TestSetup_None(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterNone})
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown failed: %v", err)
+	}
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	if _, err := Setup(context.Background(), Config{Exporter: "zipkin"}); err == nil {
+		t.Error("Expected error for unknown exporter")
+	}
+}
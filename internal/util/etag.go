@@ -0,0 +1,32 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ETagPreconditionType is the PreconditionFailure violation type reported when
+// a request's etag does not match the stored resource
+const ETagPreconditionType = "ETAG"
+
+// FormatETag returns the etag for a row version
+func FormatETag(version int64) string {
+	return strconv.FormatInt(version, 10)
+}
+
+// ETagMatches reports whether condition matches etag. condition is an etag as
+// returned by FormatETag or an If-Match/If-None-Match header value: "*", or a
+// comma-separated list of quoted, optionally weak, tags.
+func ETagMatches(condition, etag string) bool {
+	for _, tag := range strings.Split(condition, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		tag = strings.Trim(strings.TrimPrefix(tag, "W/"), `"`)
+		if tag != "" && tag == etag {
+			return true
+		}
+	}
+	return false
+}
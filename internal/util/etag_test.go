@@ -0,0 +1,30 @@
+package util
+
+import "testing"
+
+func TestETagMatches(t *testing.T) {
+	etag := FormatETag(3)
+
+	tests := []struct {
+		name      string
+		condition string
+		expected  bool
+	}{
+		{name: "Bare etag", condition: "3", expected: true},
+		{name: "Quoted header value", condition: `"3"`, expected: true},
+		{name: "Weak header value", condition: `W/"3"`, expected: true},
+		{name: "Wildcard", condition: "*", expected: true},
+		{name: "List containing etag", condition: `"1", "3"`, expected: true},
+		{name: "Stale etag", condition: `"2"`, expected: false},
+		{name: "Empty condition", condition: "", expected: false},
+		{name: "Empty quoted tag", condition: `""`, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ETagMatches(tt.condition, etag); got != tt.expected {
+				t.Errorf("ETagMatches(%q, %q) = %v, expected %v", tt.condition, etag, got, tt.expected)
+			}
+		})
+	}
+}
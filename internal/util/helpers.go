@@ -37,3 +37,19 @@ func ContainsSkill(skills []string, skill string) bool {
 	}
 	return false
 }
+
+// NormalizeSkills lower-cases and trims skills for case-insensitive matching,
+// dropping blanks and duplicates. It never returns nil.
+func NormalizeSkills(skills []string) []string {
+	normalized := make([]string, 0, len(skills))
+	seen := make(map[string]bool, len(skills))
+	for _, s := range skills {
+		skill := strings.ToLower(strings.TrimSpace(s))
+		if skill == "" || seen[skill] {
+			continue
+		}
+		seen[skill] = true
+		normalized = append(normalized, skill)
+	}
+	return normalized
+}
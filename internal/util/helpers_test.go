@@ -308,6 +308,47 @@ func TestContainsSkill(t *testing.T) {
 	}
 }
 
+func TestNormalizeSkills(t *testing.T) {
+	tests := []struct {
+		name     string
+		skills   []string
+		expected []string
+	}{
+		{
+			name:     "Nil input",
+			skills:   nil,
+			expected: []string{},
+		},
+		{
+			name:     "Lower-cases and trims",
+			skills:   []string{" Go ", "KUBERNETES"},
+			expected: []string{"go", "kubernetes"},
+		},
+		{
+			name:     "Drops blanks and duplicates",
+			skills:   []string{"Go", "", "go", "  ", "Rust"},
+			expected: []string{"go", "rust"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NormalizeSkills(tt.skills)
+			if result == nil {
+				t.Fatal("Expected non-nil slice")
+			}
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, result)
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Errorf("Expected %v, got %v", tt.expected, result)
+				}
+			}
+		})
+	}
+}
+
 func TestToNullStringRoundTrip(t *testing.T) {
 	// Test that converting to NullString and back preserves the value
 	testStrings := []string{"", "Hello", "Test@123", "Søholm-Boesen", "   "}
@@ -0,0 +1,130 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Kinds of page tokens, so a token is only accepted by the list that issued it
+const (
+	applicantsPageToken  = "applicants"
+	auditEventsPageToken = "audit_events"
+)
+
+// PageCursor is the keyset position of the last applicant on a page
+type PageCursor struct {
+	OverallScore float64   `json:"s"`
+	CreatedAt    time.Time `json:"c"`
+	ID           int64     `json:"i"`
+
+	// Filters is the hash of the ListFilters the token was issued for
+	Filters string `json:"f"`
+}
+
+// ListFilters are the ListApplicants filters a page token is bound to, so a
+// cursor is never applied to a different result set
+type ListFilters struct {
+	Position  string   `json:"p,omitempty"`
+	Status    int32    `json:"st,omitempty"`
+	MinScore  float64  `json:"m,omitempty"`
+	SkillsAny []string `json:"a,omitempty"`
+	SkillsAll []string `json:"l,omitempty"`
+}
+
+// hash returns a digest of the filters; the order of skills does not matter
+func (f ListFilters) hash() string {
+	f.SkillsAny = sortedCopy(f.SkillsAny)
+	f.SkillsAll = sortedCopy(f.SkillsAll)
+	data, _ := json.Marshal(f)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:16])
+}
+
+// sortedCopy returns a sorted copy of values
+func sortedCopy(values []string) []string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// AuditCursor is the keyset position of the last audit event on a page
+type AuditCursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        int64     `json:"i"`
+}
+
+// pageToken is the encoded form of a cursor, tagged with the list it belongs to
+type pageToken struct {
+	Kind   string          `json:"k"`
+	Cursor json.RawMessage `json:"p"`
+}
+
+// EncodePageToken encodes a cursor into an opaque, URL-safe page token for
+// the list selected by filters
+func EncodePageToken(cursor PageCursor, filters ListFilters) string {
+	cursor.Filters = filters.hash()
+	return encodePageToken(applicantsPageToken, cursor)
+}
+
+// DecodePageToken decodes a page token produced by EncodePageToken. The token
+// is rejected unless it was issued for the same filters.
+func DecodePageToken(token string, filters ListFilters) (PageCursor, error) {
+	var cursor PageCursor
+	if err := decodePageToken(token, applicantsPageToken, &cursor); err != nil {
+		return cursor, err
+	}
+	if cursor.ID <= 0 || cursor.CreatedAt.IsZero() {
+		return cursor, fmt.Errorf("incomplete page token")
+	}
+	if cursor.Filters != filters.hash() {
+		return cursor, fmt.Errorf("page token was issued for different filters")
+	}
+	return cursor, nil
+}
+
+// EncodeAuditPageToken encodes an audit cursor into an opaque, URL-safe page token
+func EncodeAuditPageToken(cursor AuditCursor) string {
+	return encodePageToken(auditEventsPageToken, cursor)
+}
+
+// DecodeAuditPageToken decodes a page token produced by EncodeAuditPageToken
+func DecodeAuditPageToken(token string) (AuditCursor, error) {
+	var cursor AuditCursor
+	if err := decodePageToken(token, auditEventsPageToken, &cursor); err != nil {
+		return cursor, err
+	}
+	if cursor.ID <= 0 || cursor.CreatedAt.IsZero() {
+		return cursor, fmt.Errorf("incomplete page token")
+	}
+	return cursor, nil
+}
+
+// encodePageToken encodes cursor as a page token of the given kind
+func encodePageToken(kind string, cursor interface{}) string {
+	payload, _ := json.Marshal(cursor)
+	data, _ := json.Marshal(pageToken{Kind: kind, Cursor: payload})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodePageToken decodes a page token of the given kind into cursor
+func decodePageToken(token, kind string, cursor interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return fmt.Errorf("malformed page token")
+	}
+	var t pageToken
+	if err := json.Unmarshal(data, &t); err != nil {
+		return fmt.Errorf("malformed page token")
+	}
+	if t.Kind != kind {
+		return fmt.Errorf("page token belongs to another list")
+	}
+	if err := json.Unmarshal(t.Cursor, cursor); err != nil {
+		return fmt.Errorf("malformed page token")
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPageToken_RoundTrip(t *testing.T) {
+	cursor := PageCursor{
+		OverallScore: 87.25,
+		CreatedAt:    time.Date(2025, 3, 14, 15, 9, 26, 535897000, time.UTC),
+		ID:           42,
+	}
+
+	filters := ListFilters{Position: "Developer", MinScore: 50, SkillsAny: []string{"go", "rust"}}
+
+	token := EncodePageToken(cursor, filters)
+	if token == "" {
+		t.Fatal("Expected non-empty token")
+	}
+
+	// The order of skills does not change the filters
+	filters.SkillsAny = []string{"rust", "go"}
+	decoded, err := DecodePageToken(token, filters)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if decoded.OverallScore != cursor.OverallScore {
+		t.Errorf("Expected score %.2f, got %.2f", cursor.OverallScore, decoded.OverallScore)
+	}
+	if !decoded.CreatedAt.Equal(cursor.CreatedAt) {
+		t.Errorf("Expected created_at %v, got %v", cursor.CreatedAt, decoded.CreatedAt)
+	}
+	if decoded.ID != cursor.ID {
+		t.Errorf("Expected ID %d, got %d", cursor.ID, decoded.ID)
+	}
+}
+
+func TestDecodePageToken_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "Not base64", token: "!!not-a-token!!"},
+		{name: "Not JSON", token: "bm90LWpzb24"},
+		{name: "Missing ID", token: EncodePageToken(PageCursor{OverallScore: 50, CreatedAt: time.Now()}, ListFilters{})},
+		{name: "Missing created_at", token: EncodePageToken(PageCursor{OverallScore: 50, ID: 1}, ListFilters{})},
+		{name: "Different filters", token: EncodePageToken(PageCursor{OverallScore: 50, CreatedAt: time.Now(), ID: 1}, ListFilters{Status: 2})},
+		{name: "Token without filters", token: encodePageToken(applicantsPageToken, PageCursor{OverallScore: 50, CreatedAt: time.Now(), ID: 1})},
+		{name: "Audit event token", token: EncodeAuditPageToken(AuditCursor{CreatedAt: time.Now(), ID: 1})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodePageToken(tt.token, ListFilters{}); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestAuditPageToken(t *testing.T) {
+	cursor := AuditCursor{CreatedAt: time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC), ID: 42}
+
+	decoded, err := DecodeAuditPageToken(EncodeAuditPageToken(cursor))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if decoded != cursor {
+		t.Errorf("Expected %+v, got %+v", cursor, decoded)
+	}
+
+	applicantsToken := EncodePageToken(PageCursor{OverallScore: 50, CreatedAt: time.Now(), ID: 1}, ListFilters{})
+	if _, err := DecodeAuditPageToken(applicantsToken); err == nil {
+		t.Error("Expected a ListApplicants token to be rejected")
+	}
+}
@@ -1,34 +1,137 @@
 package util
 
 import (
+	"database/sql"
+
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	applicantsv1 "github.com/Thrun12/golang-assignment/api/proto/v1"
 	"github.com/Thrun12/golang-assignment/internal/db/sqlc"
+	"github.com/Thrun12/golang-assignment/internal/scoring"
 )
 
 // DbApplicantToProto converts a database applicant to protobuf format
 func DbApplicantToProto(app *sqlc.Applicant) *applicantsv1.JobApplicant {
 	return &applicantsv1.JobApplicant{
-		Id:                 app.ID,
-		Name:               app.Name,
-		Email:              app.Email,
-		Position:           app.Position,
-		YearsExperience:    app.YearsExperience,
-		Skills:             app.Skills,
-		GithubStars:        app.GithubStars,
-		CanExitVim:         app.CanExitVim,
-		KnowsGo:            app.KnowsGo,
-		DebugsInProduction: app.DebugsInProduction,
-		InterviewScore:     RoundToTwoDecimals(app.InterviewScore),
-		CulturalFitScore:   RoundToTwoDecimals(app.CulturalFitScore),
-		TechnicalScore:     RoundToTwoDecimals(app.TechnicalScore),
-		OverallScore:       RoundToTwoDecimals(app.OverallScore),
-		Status:             applicantsv1.ApplicantStatus(app.Status),
-		FunFact:            NullStringToString(app.FunFact),
-		Availability:       NullStringToString(app.Availability),
-		SalaryExpectation:  NullStringToString(app.SalaryExpectation),
-		CreatedAt:          timestamppb.New(app.CreatedAt),
-		UpdatedAt:          timestamppb.New(app.UpdatedAt),
+		Id:                  app.ID,
+		Name:                app.Name,
+		Email:               app.Email,
+		Position:            app.Position,
+		YearsExperience:     app.YearsExperience,
+		Skills:              app.Skills,
+		GithubStars:         app.GithubStars,
+		CanExitVim:          app.CanExitVim,
+		KnowsGo:             app.KnowsGo,
+		DebugsInProduction:  app.DebugsInProduction,
+		InterviewScore:      RoundToTwoDecimals(app.InterviewScore),
+		CulturalFitScore:    RoundToTwoDecimals(app.CulturalFitScore),
+		TechnicalScore:      RoundToTwoDecimals(app.TechnicalScore),
+		OverallScore:        RoundToTwoDecimals(app.OverallScore),
+		Status:              applicantsv1.ApplicantStatus(app.Status),
+		FunFact:             NullStringToString(app.FunFact),
+		Availability:        NullStringToString(app.Availability),
+		SalaryExpectation:   NullStringToString(app.SalaryExpectation),
+		CreatedAt:           timestamppb.New(app.CreatedAt),
+		UpdatedAt:           timestamppb.New(app.UpdatedAt),
+		ScoringModelVersion: app.ScoringModelVersion,
+		Etag:                FormatETag(app.Version),
+		Agency:              NullStringToString(app.Agency),
+	}
+}
+
+// ApiKeyToProto converts a database API key to protobuf format, without its hash
+func ApiKeyToProto(key *sqlc.ApiKey) *applicantsv1.ApiKey {
+	return &applicantsv1.ApiKey{
+		Id:         key.ID,
+		Name:       key.Name,
+		Owner:      key.Owner,
+		KeyPrefix:  key.KeyPrefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  NullTimeToTimestamp(key.ExpiresAt),
+		LastUsedAt: NullTimeToTimestamp(key.LastUsedAt),
+		RevokedAt:  NullTimeToTimestamp(key.RevokedAt),
+		CreatedAt:  timestamppb.New(key.CreatedAt),
+	}
+}
+
+// NullTimeToTimestamp converts sql.NullTime to a timestamp, nil if unset
+func NullTimeToTimestamp(nt sql.NullTime) *timestamppb.Timestamp {
+	if !nt.Valid {
+		return nil
+	}
+	return timestamppb.New(nt.Time)
+}
+
+// StatusHistoryEntryToProto converts a database status history entry to protobuf format
+func StatusHistoryEntryToProto(entry *sqlc.ApplicantStatusHistory) *applicantsv1.StatusHistoryEntry {
+	return &applicantsv1.StatusHistoryEntry{
+		Id:          entry.ID,
+		ApplicantId: entry.ApplicantID,
+		FromStatus:  applicantsv1.ApplicantStatus(entry.FromStatus),
+		ToStatus:    applicantsv1.ApplicantStatus(entry.ToStatus),
+		ChangedBy:   entry.ChangedBy,
+		Reason:      NullStringToString(entry.Reason),
+		ChangedAt:   timestamppb.New(entry.ChangedAt),
+	}
+}
+
+// ScoringModelToProto converts a scoring model to protobuf format
+func ScoringModelToProto(m *scoring.Model, isDefault bool) *applicantsv1.ScoringModel {
+	rules := make([]*applicantsv1.ScoringRule, len(m.Rules))
+	for i, r := range m.Rules {
+		rules[i] = &applicantsv1.ScoringRule{
+			Name: r.Name,
+			When: &applicantsv1.ScoringCondition{
+				HasSkills:          r.When.HasSkills,
+				LacksSkills:        r.When.LacksSkills,
+				KnowsGo:            r.When.KnowsGo,
+				CanExitVim:         r.When.CanExitVim,
+				DebugsInProduction: r.When.DebugsInProduction,
+				MinYearsExperience: r.When.MinYearsExperience,
+			},
+			Add:               r.Add,
+			Multiply:          r.Multiply,
+			PerYearExperience: r.PerYearExperience,
+			PerSkillAbove:     r.PerSkillAbove,
+			PerSkill:          r.PerSkill,
+			Max:               r.Max,
+		}
+	}
+
+	return &applicantsv1.ScoringModel{
+		Version:     m.Version,
+		Description: m.Description,
+		Positions:   m.Positions,
+		Weights: &applicantsv1.ScoringWeights{
+			Technical:   m.Weights.Technical,
+			Interview:   m.Weights.Interview,
+			CulturalFit: m.Weights.CulturalFit,
+		},
+		Rules:     rules,
+		IsDefault: isDefault,
+	}
+}
+
+// ScoreBreakdownToProto converts a score breakdown to protobuf format
+func ScoreBreakdownToProto(b *scoring.Breakdown) *applicantsv1.ScoreBreakdown {
+	adjustments := make([]*applicantsv1.ScoreAdjustment, len(b.Adjustments))
+	for i, a := range b.Adjustments {
+		adjustments[i] = &applicantsv1.ScoreAdjustment{
+			Rule:       a.Rule,
+			Added:      a.Added,
+			Multiplier: a.Multiplier,
+			Delta:      a.Delta,
+			ScoreAfter: a.ScoreAfter,
+		}
+	}
+
+	return &applicantsv1.ScoreBreakdown{
+		ScoringModelVersion: b.ModelVersion,
+		BaseScore:           b.BaseScore,
+		Adjustments:         adjustments,
+		UnclampedScore:      b.UnclampedScore,
+		Clamped:             b.Clamped,
+		UnroundedScore:      b.UnroundedScore,
+		FinalScore:          b.FinalScore,
 	}
 }
@@ -1,63 +1,61 @@
 package util
 
-import (
-	"fmt"
-	"net/mail"
-	"strings"
-)
-
-// ValidateApplicant validates applicant fields for both create and update requests
-func ValidateApplicant(name, email, position string, yearsExperience, githubStars int32, interviewScore, culturalFitScore, technicalScore float64, isUpdate bool, id int64) error {
-	// Validate ID for update requests
-	if isUpdate && id <= 0 {
-		return fmt.Errorf("id must be positive")
-	}
+import "strings"
 
-	// Validate name
-	if strings.TrimSpace(name) == "" {
-		return fmt.Errorf("name is required")
-	}
-	if len(name) < 2 {
-		return fmt.Errorf("name must be at least 2 characters")
-	}
-	if len(name) > 255 {
-		return fmt.Errorf("name must be at most 255 characters")
-	}
+// FieldViolation describes why a single request field is invalid
+type FieldViolation struct {
+	Field       string
+	Description string
+}
 
-	// Validate email
-	if strings.TrimSpace(email) == "" {
-		return fmt.Errorf("email is required")
-	}
-	if _, err := mail.ParseAddress(email); err != nil {
-		return fmt.Errorf("email must be a valid email address")
-	}
+// ValidationError collects every field violation found in a request
+type ValidationError struct {
+	Violations []FieldViolation
+}
 
-	// Validate position
-	if strings.TrimSpace(position) == "" {
-		return fmt.Errorf("position is required")
-	}
-	if len(position) < 2 {
-		return fmt.Errorf("position must be at least 2 characters")
+// Error joins the violation descriptions
+func (e *ValidationError) Error() string {
+	descriptions := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		descriptions[i] = v.Description
 	}
+	return strings.Join(descriptions, "; ")
+}
 
-	// Validate years experience and github stars
-	if yearsExperience < 0 {
-		return fmt.Errorf("years_experience must be positive")
-	}
-	if githubStars < 0 {
-		return fmt.Errorf("github_stars must be positive")
+// Add records a violation for field
+func (e *ValidationError) Add(field, description string) {
+	e.Violations = append(e.Violations, FieldViolation{Field: field, Description: description})
+}
+
+// Err returns e if any violation was recorded, nil otherwise
+func (e *ValidationError) Err() error {
+	if len(e.Violations) == 0 {
+		return nil
 	}
+	return e
+}
+
+// NewFieldError returns a validation error with a single violation
+func NewFieldError(field, description string) error {
+	verr := &ValidationError{}
+	verr.Add(field, description)
+	return verr
+}
 
-	// Validate scores
-	if interviewScore < 0 || interviewScore > 100 {
-		return fmt.Errorf("interview_score must be between 0 and 100")
+// ValidateRequiredApplicantFields reports every empty field an applicant cannot
+// be stored without. Field formats are enforced by the protovalidate rules on
+// the request messages, which let updates leave name, email and position empty
+// when the update_mask excludes them; the merged update is checked here.
+func ValidateRequiredApplicantFields(name, email, position string) error {
+	verr := &ValidationError{}
+	if name == "" {
+		verr.Add("name", "name is required")
 	}
-	if culturalFitScore < 0 || culturalFitScore > 100 {
-		return fmt.Errorf("cultural_fit_score must be between 0 and 100")
+	if email == "" {
+		verr.Add("email", "email is required")
 	}
-	if technicalScore < 0 || technicalScore > 100 {
-		return fmt.Errorf("technical_score must be between 0 and 100")
+	if position == "" {
+		verr.Add("position", "position is required")
 	}
-
-	return nil
+	return verr.Err()
 }